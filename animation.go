@@ -0,0 +1,276 @@
+package plots
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	vgdraw "gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/vgimg"
+)
+
+// Animation is the timing of an animated plot. Each frame shows the data up
+// to the frame time, over the whole animated time range or over a sliding
+// window ending at the frame time.
+type Animation struct {
+	Start, End float64 // Animated time range (default = plot X range).
+	Step       float64 // Time step between frames (default = time range / 100).
+	Window     float64 // Sliding window duration, whole time range if 0.
+	FrameRate  float64 // Frames per second (default = 10).
+}
+
+// frameTimes returns the frame times for the plot X range [xMin,xMax].
+func (a Animation) frameTimes(xMin, xMax float64) ([]float64, error) {
+	start, end := a.Start, a.End
+	if start == 0 && end == 0 {
+		start, end = xMin, xMax
+	}
+	if !(end > start) || math.IsInf(end-start, 0) {
+		return nil, fmt.Errorf("empty animation time range")
+	}
+	step := a.Step
+	if step <= 0 {
+		step = (end - start) / 100
+	}
+	first := start
+	if a.Window > 0 {
+		first = min(start+a.Window, end)
+	}
+	n := int(math.Floor((end-first)/step+1e-9)) + 1
+	times := make([]float64, n)
+	for i := range times {
+		times[i] = first + float64(i)*step
+	}
+	return times, nil
+}
+
+// frameRange returns the X range of the frame at time t.
+func (a Animation) frameRange(t, start float64) (xMin, xMax float64) {
+	if a.Window > 0 {
+		return t - a.Window, t
+	}
+	return start, a.End
+}
+
+// delay returns the frame delay in 100ths of second.
+func (a Animation) delay() int {
+	frameRate := a.FrameRate
+	if frameRate <= 0 {
+		frameRate = 10
+	}
+	return max(int(math.Round(100/frameRate)), 1)
+}
+
+// MakeSpikeAnimation generates the animation of the spike plot. Each frame
+// shows the spikes up to the frame time. The animation is saved as an
+// animated GIF when the file name extension is .gif, and as a sequence of
+// numbered PNG files otherwise (see FrameFileName).
+func MakeSpikeAnimation(spikeLines SpikeLines, a Animation, fileNames ...string) error {
+	if err := spikeLines.checkUnits(); err != nil {
+		return fmt.Errorf("spike animation: %w", err)
+	}
+	xMin, xMax := spikeLines.xRange()
+	times, err := a.frameTimes(xMin, xMax)
+	if err != nil {
+		return fmt.Errorf("spike animation: %w", err)
+	}
+	if a.Start == 0 && a.End == 0 {
+		a.Start, a.End = xMin, xMax
+	}
+	frame := func(i int) func(dc vgdraw.Canvas) {
+		s := spikeLines
+		s.Lines = make([]SpikeLine, len(spikeLines.Lines))
+		for k, l := range spikeLines.Lines {
+			l.Spikes = l.Spikes[:sort.Search(len(l.Spikes), func(j int) bool { return l.Spikes[j] > times[i] })]
+			s.Lines[k] = l
+		}
+		xMin, xMax := a.frameRange(times[i], a.Start)
+		s.XLimit = &Limit{Min: xMin, Max: xMax}
+		return NewSpikePlot(s).Draw
+	}
+	xDim, yDim := plotDims(spikeLines.XDim, spikeLines.YDim)
+	err = saveAnimation(len(times), frame, a.delay(), xDim, yDim, fileNames...)
+	if err != nil {
+		return fmt.Errorf("spike animation: %w", err)
+	}
+	return nil
+}
+
+// MakeLineAnimation generates the animation of the line plot. Each frame
+// shows the line points whose X value is at most the frame time, with their
+// uncertainties and FillTo points, and the Y range of the whole plot. The animation is saved as MakeSpikeAnimation
+// does.
+func MakeLineAnimation(lines Lines, a Animation, fileNames ...string) error {
+	full, err := NewLinePlot(lines)
+	if err != nil {
+		return fmt.Errorf("line animation: %w", err)
+	}
+	times, err := a.frameTimes(full.X.Min, full.X.Max)
+	if err != nil {
+		return fmt.Errorf("line animation: %w", err)
+	}
+	if a.Start == 0 && a.End == 0 {
+		a.Start, a.End = full.X.Min, full.X.Max
+	}
+	var frameErr error
+	frame := func(i int) func(dc vgdraw.Canvas) {
+		l := lines
+		l.Lines = make([]Line, len(lines.Lines))
+		for k, line := range lines.Lines {
+			l.Lines[k] = line.until(times[i])
+		}
+		p, err := NewLinePlot(l)
+		if err != nil {
+			frameErr = err
+			return func(vgdraw.Canvas) {}
+		}
+		p.X.Min, p.X.Max = a.frameRange(times[i], a.Start)
+		p.Y.Min, p.Y.Max = full.Y.Min, full.Y.Max
+		return p.Draw
+	}
+	xDim, yDim := plotDims(lines.XDim, lines.YDim)
+	err = saveAnimation(len(times), frame, a.delay(), xDim, yDim, fileNames...)
+	if err == nil {
+		err = frameErr
+	}
+	if err != nil {
+		return fmt.Errorf("line animation: %w", err)
+	}
+	return nil
+}
+
+// until returns the line with the points whose X value is at most t, with
+// their uncertainties, and the FillTo points whose X value is at most t.
+func (line Line) until(t float64) Line {
+	var points plotter.XYs
+	var xErrors, yErrors []float64
+	if line.XErrors != nil {
+		xErrors = []float64{}
+	}
+	if line.YErrors != nil {
+		yErrors = []float64{}
+	}
+	for j := 0; j < line.Points.Len(); j++ {
+		x, y := line.Points.XY(j)
+		if x > t {
+			continue
+		}
+		points = append(points, plotter.XY{X: x, Y: y})
+		if j < len(line.XErrors) {
+			xErrors = append(xErrors, line.XErrors[j])
+		}
+		if j < len(line.YErrors) {
+			yErrors = append(yErrors, line.YErrors[j])
+		}
+	}
+	line.Points, line.XErrors, line.YErrors = points, xErrors, yErrors
+	if line.FillTo != nil {
+		var fillTo plotter.XYs
+		for j := 0; j < line.FillTo.Len(); j++ {
+			if x, y := line.FillTo.XY(j); x <= t {
+				fillTo = append(fillTo, plotter.XY{X: x, Y: y})
+			}
+		}
+		line.FillTo = fillTo
+	}
+	return line
+}
+
+// FrameFileName returns the file name of frame i of a PNG sequence. The
+// frame number is formatted with the verb of the file name if it contains
+// one, like "frames/%03d.png", or inserted with 4 digits before the file
+// name extension otherwise.
+func FrameFileName(fileName string, i int) string {
+	if strings.Contains(fileName, "%") {
+		return fmt.Sprintf(fileName, i)
+	}
+	ext := filepath.Ext(fileName)
+	return fmt.Sprintf("%s%04d%s", strings.TrimSuffix(fileName, ext), i, ext)
+}
+
+// renderImage returns the image of the drawing made by drawFn in a canvas of
+// the given size.
+func renderImage(xDim, yDim vg.Length, drawFn func(dc vgdraw.Canvas)) image.Image {
+	c := vgimg.New(xDim, yDim)
+	drawFn(vgdraw.New(c))
+	return c.Image()
+}
+
+// saveAnimation saves the n frames drawn by the drawing functions returned
+// by frame, with the given frame delay in 100ths of second.
+func saveAnimation(n int, frame func(i int) func(dc vgdraw.Canvas), delay int, xDim, yDim vg.Length, fileNames ...string) error {
+	if len(fileNames) == 0 {
+		return nil
+	}
+	var anim gif.GIF
+	var hasGIF bool
+	for _, fileName := range fileNames {
+		hasGIF = hasGIF || strings.EqualFold(filepath.Ext(fileName), ".gif")
+	}
+	for i := 0; i < n; i++ {
+		img := renderImage(xDim, yDim, frame(i))
+		for _, fileName := range fileNames {
+			if strings.EqualFold(filepath.Ext(fileName), ".gif") {
+				continue
+			}
+			if err := savePNG(FrameFileName(fileName, i), img); err != nil {
+				return err
+			}
+		}
+		if hasGIF {
+			paletted := image.NewPaletted(img.Bounds(), palette.Plan9)
+			draw.Draw(paletted, paletted.Rect, img, img.Bounds().Min, draw.Src)
+			anim.Image = append(anim.Image, paletted)
+			anim.Delay = append(anim.Delay, delay)
+		}
+	}
+	for _, fileName := range fileNames {
+		if !strings.EqualFold(filepath.Ext(fileName), ".gif") {
+			continue
+		}
+		if err := saveGIF(fileName, &anim); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// savePNG saves the image in a PNG file.
+func savePNG(fileName string, img image.Image) (err error) {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := f.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	return png.Encode(f, img)
+}
+
+// saveGIF saves the animation in a GIF file.
+func saveGIF(fileName string, anim *gif.GIF) (err error) {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := f.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	return gif.EncodeAll(f, anim)
+}
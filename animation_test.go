@@ -0,0 +1,89 @@
+package plots
+
+import (
+	"fmt"
+	"image/gif"
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/vg"
+)
+
+func TestFrameFileName(t *testing.T) {
+	if name := FrameFileName("tests/frame.png", 12); name != "tests/frame0012.png" {
+		t.Errorf("got %q, expected tests/frame0012.png", name)
+	}
+	if name := FrameFileName("tests/frame-%03d.png", 12); name != "tests/frame-012.png" {
+		t.Errorf("got %q, expected tests/frame-012.png", name)
+	}
+}
+
+func TestSpikeAnimation(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var spikes SpikeLines
+	for i := 0; i < 10; i++ {
+		spikes.Lines = append(spikes.Lines, SpikeLine{
+			Label:  fmt.Sprintf("%d", i+1),
+			Spikes: GeneratePoissonDistributedSpikes(4, 8, 0.02),
+		})
+	}
+	spikes.Title = "Sliding window"
+	spikes.XDim, spikes.YDim = 8*vg.Centimeter, 6*vg.Centimeter
+	a := Animation{Start: 0, End: 4, Step: 0.25, Window: 1, FrameRate: 5}
+	err := MakeSpikeAnimation(spikes, a, "tests/spikeAnimation.gif", "tests/spikeAnimation-%02d.png")
+	if err != nil {
+		t.Fatalf("failed saving animation: %s", err)
+	}
+	f, err := os.Open("tests/spikeAnimation.gif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("failed decoding animation: %s", err)
+	}
+	if len(anim.Image) != 13 || anim.Delay[0] != 20 {
+		t.Errorf("got %d frames with delay %d, expected 13 frames with delay 20", len(anim.Image), anim.Delay[0])
+	}
+	if _, err := os.Stat("tests/spikeAnimation-12.png"); err != nil {
+		t.Errorf("missing last frame: %s", err)
+	}
+}
+
+func TestLineAnimation(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	sin := make([]float64, 200)
+	for i := range sin {
+		sin[i] = math.Sin(float64(i) / 20)
+	}
+	lines := Lines{
+		Title: "Revealed line",
+		Lines: []Line{{Label: "sin", Points: XYs(sin), Glyph: Glyphs.Id(0), Color: DarkColors.Id(2)}},
+		XDim:  8 * vg.Centimeter,
+		YDim:  6 * vg.Centimeter,
+	}
+	err := MakeLineAnimation(lines, Animation{Step: 10}, "tests/lineAnimation.gif")
+	if err != nil {
+		t.Fatalf("failed saving animation: %s", err)
+	}
+	errs := make([]float64, len(sin))
+	for i := range errs {
+		errs[i] = 0.1
+	}
+	lines.Title = "Revealed uncertainties"
+	lines.Lines = []Line{
+		{Label: "sin", Points: XYs(sin), Color: DarkColors.Id(2), YErrors: errs, XErrors: errs},
+		{Label: "band", Points: XYs(sin), Color: DarkColors.Id(1), YErrors: errs, Band: true},
+		{Label: "fill", Points: XYs(sin), Color: DarkColors.Id(3), Mode: FillBetweenMode, FillTo: XYs(make([]float64, len(sin)))},
+	}
+	err = MakeLineAnimation(lines, Animation{Step: 40}, "tests/errorLineAnimation.gif")
+	if err != nil {
+		t.Fatalf("failed saving animation: %s", err)
+	}
+	err = MakeLineAnimation(Lines{}, Animation{}, "tests/emptyAnimation.gif")
+	if err == nil {
+		t.Fatalf("expected error with empty time range")
+	}
+}
@@ -0,0 +1,259 @@
+package plots
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Predictor is a correlogram baseline predictor type.
+type Predictor int
+
+const (
+	NoPredictor      Predictor = iota // No baseline.
+	ShufflePredictor                  // Target with shuffled inter-spike intervals.
+	JitterPredictor                   // Target with uniformly jittered spikes.
+)
+
+// Correlogram is a cross-correlogram of two spike lines, or the
+// auto-correlogram of a spike line when Target is nil. The histogram counts
+// the target spikes at a given lag from the reference spikes.
+type Correlogram struct {
+	Title     string      // Title.
+	Ref       SpikeLine   // Reference spike line.
+	Target    *SpikeLine  // Target spike line, auto-correlogram if nil.
+	Window    float64     // Maximum absolute lag (default = 0.1).
+	BinWidth  float64     // Bin width (default = Window / 25).
+	Rate      bool        // Normalize counts to target rates in Hz.
+	Predictor Predictor   // Baseline predictor (default = NoPredictor).
+	Jitter    float64     // Jitter predictor half window (default = Window / 2).
+	Surrogate int         // Number of predictor surrogates (default = 100).
+	Sigmas    float64     // Significance band half width in std (default = 2).
+	Rand      *rand.Rand  // Surrogate random source (default = fixed seed).
+	Color     color.Color // Bar fill color (default = grey).
+	BColor    color.Color // Baseline and band color (default = red).
+	XDim      vg.Length   // X dimension of saved plot, use default if 0.
+	YDim      vg.Length   // Y dimension of saved plot, use default if 0.
+}
+
+// params returns the window, bin width and number of bins with default
+// values when needed.
+func (c Correlogram) params() (window, binWidth float64, nBins int) {
+	window = c.Window
+	if window <= 0 {
+		window = 0.1
+	}
+	binWidth = c.BinWidth
+	if binWidth <= 0 {
+		binWidth = window / 25
+	}
+	nBins = int(math.Ceil(2 * window / binWidth))
+	return window, binWidth, nBins
+}
+
+// target returns the target spike times.
+func (c Correlogram) target() []float64 {
+	if c.Target == nil {
+		return c.Ref.Spikes
+	}
+	return c.Target.Spikes
+}
+
+// lagCounts returns the counts of target spikes at lags in [-window,window)
+// from the reference spikes. Coincident self pairs are ignored when auto
+// is true.
+func lagCounts(ref, target []float64, window, binWidth float64, nBins int, auto bool) []float64 {
+	counts := make([]float64, nBins)
+	var beg int
+	for i, r := range ref {
+		for beg < len(target) && target[beg] < r-window {
+			beg++
+		}
+		for j := beg; j < len(target) && target[j] < r+window; j++ {
+			if auto && i == j {
+				continue
+			}
+			k := int((target[j] - r + window) / binWidth)
+			if k >= 0 && k < nBins {
+				counts[k]++
+			}
+		}
+	}
+	return counts
+}
+
+// normalize returns the factor converting counts to the plotted unit.
+func (c Correlogram) normalize(binWidth float64) float64 {
+	if !c.Rate || len(c.Ref.Spikes) == 0 {
+		return 1
+	}
+	return 1 / (binWidth * float64(len(c.Ref.Spikes)))
+}
+
+// Bins returns the correlogram histogram bins. The bin weights are the spike
+// counts, or the target rate in Hz when Rate is true.
+func (c Correlogram) Bins() []plotter.HistogramBin {
+	window, binWidth, nBins := c.params()
+	counts := lagCounts(c.Ref.Spikes, c.target(), window, binWidth, nBins, c.Target == nil)
+	norm := c.normalize(binWidth)
+	bins := make([]plotter.HistogramBin, nBins)
+	for i := range bins {
+		bins[i].Min = -window + float64(i)*binWidth
+		bins[i].Max = bins[i].Min + binWidth
+		bins[i].Weight = counts[i] * norm
+	}
+	return bins
+}
+
+// Surrogates returns the mean and standard deviation per bin of the
+// correlograms of the reference with the predictor surrogates of the target.
+// The shuffled intervals of ShufflePredictor start at a random offset in the
+// recording window, the time range of the reference and target spikes, and
+// wrap around its end. Returns nil slices when Predictor is NoPredictor.
+func (c Correlogram) Surrogates() (mean, std []float64) {
+	if c.Predictor == NoPredictor {
+		return nil, nil
+	}
+	window, binWidth, nBins := c.params()
+	n := c.Surrogate
+	if n <= 0 {
+		n = 100
+	}
+	jitter := c.Jitter
+	if jitter <= 0 {
+		jitter = window / 2
+	}
+	rng := c.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(1, 2))
+	}
+	target := c.target()
+	intervals := Intervals(target)
+	start, end := spikesRange([]SpikeLine{c.Ref, {Spikes: target}})
+	surrogate := make([]float64, len(target))
+	sum := make([]float64, nBins)
+	sum2 := make([]float64, nBins)
+	for range n {
+		switch c.Predictor {
+		case ShufflePredictor:
+			rng.Shuffle(len(intervals), func(i, j int) {
+				intervals[i], intervals[j] = intervals[j], intervals[i]
+			})
+			if !(end > start) {
+				copy(surrogate, target)
+				break
+			}
+			t := (end - start) * rng.Float64()
+			for i := range surrogate {
+				surrogate[i] = start + math.Mod(t, end-start)
+				if i < len(intervals) {
+					t += intervals[i]
+				}
+			}
+			slices.Sort(surrogate)
+		case JitterPredictor:
+			for i, v := range target {
+				surrogate[i] = v + (2*rng.Float64()-1)*jitter
+			}
+			slices.Sort(surrogate)
+		}
+		// surrogate spikes are independent of the reference
+		counts := lagCounts(c.Ref.Spikes, surrogate, window, binWidth, nBins, false)
+		for i, v := range counts {
+			sum[i] += v
+			sum2[i] += v * v
+		}
+	}
+	norm := c.normalize(binWidth)
+	mean = make([]float64, nBins)
+	std = make([]float64, nBins)
+	for i := range mean {
+		m := sum[i] / float64(n)
+		mean[i] = m * norm
+		std[i] = math.Sqrt(max(sum2[i]/float64(n)-m*m, 0)) * norm
+	}
+	return mean, std
+}
+
+// newCorrelogramPlot returns the correlogram plot.
+func newCorrelogramPlot(c Correlogram) *plot.Plot {
+	p := plot.New()
+	p.Title.Text = c.Title
+	p.X.Label.Text = "Lag (s)"
+	if c.Rate {
+		p.Y.Label.Text = "Rate (Hz)"
+	} else {
+		p.Y.Label.Text = "Count"
+	}
+	bins := c.Bins()
+	hist := &plotter.Histogram{
+		Bins:      bins,
+		Width:     bins[0].Max - bins[0].Min,
+		FillColor: color.RGBA{160, 160, 160, 255},
+		LineStyle: draw.LineStyle{
+			Color: color.RGBA{0, 0, 0, 255},
+			Width: vg.Points(1),
+		},
+	}
+	if c.Color != nil {
+		hist.FillColor = c.Color
+	}
+	p.Add(hist)
+	mean, std := c.Surrogates()
+	if mean == nil {
+		return p
+	}
+	bColor := c.BColor
+	if bColor == nil {
+		bColor = color.RGBA{238, 46, 47, 255}
+	}
+	sigmas := c.Sigmas
+	if sigmas <= 0 {
+		sigmas = 2
+	}
+	baseline := make(plotter.XYs, len(bins))
+	band := make(plotter.XYs, 2*len(bins))
+	for i := range bins {
+		x := (bins[i].Min + bins[i].Max) / 2
+		baseline[i] = plotter.XY{X: x, Y: mean[i]}
+		band[i] = plotter.XY{X: x, Y: mean[i] + sigmas*std[i]}
+		band[len(band)-1-i] = plotter.XY{X: x, Y: max(mean[i]-sigmas*std[i], 0)}
+	}
+	poly, err := plotter.NewPolygon(band)
+	if err == nil {
+		poly.Color = withAlpha(bColor, 64)
+		poly.LineStyle.Width = 0
+		p.Add(poly)
+	}
+	l := &plotter.Line{
+		XYs: baseline,
+		LineStyle: draw.LineStyle{
+			Color:  bColor,
+			Width:  vg.Points(1.5),
+			Dashes: Dashes.Id(0),
+		},
+	}
+	p.Add(l)
+	return p
+}
+
+// MakeCorrelogramPlot generates the cross-correlogram plot, or the
+// auto-correlogram plot when Target is nil.
+func MakeCorrelogramPlot(c Correlogram, fileNames ...string) error {
+	p := newCorrelogramPlot(c)
+	xDim, yDim := plotDims(c.XDim, c.YDim)
+	for _, fileName := range fileNames {
+		err := p.Save(xDim, yDim, fileName)
+		if err != nil {
+			return fmt.Errorf("correlogram plot: %w", err)
+		}
+	}
+	return nil
+}
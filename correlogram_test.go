@@ -0,0 +1,84 @@
+package plots
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+func TestCorrelogramBins(t *testing.T) {
+	ref := SpikeLine{Spikes: []float64{1, 2, 3}}
+	target := SpikeLine{Spikes: []float64{1.01, 2.01, 3.01, 3.5}}
+	c := Correlogram{
+		Ref:      ref,
+		Target:   &target,
+		Window:   0.1,
+		BinWidth: 0.02,
+	}
+	bins := c.Bins()
+	if len(bins) != 10 {
+		t.Fatalf("got %d bins, expected 10", len(bins))
+	}
+	if bins[5].Weight != 3 {
+		t.Fatalf("got %v spikes in lag bin [0,0.02), expected 3", bins[5].Weight)
+	}
+	c.Target = nil
+	for _, b := range c.Bins() {
+		if b.Weight != 0 {
+			t.Fatalf("got %v spikes in auto-correlogram bin [%v,%v), expected 0", b.Weight, b.Min, b.Max)
+		}
+	}
+}
+
+func TestCorrelogramPlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	duration := 20.
+	common := GeneratePoissonDistributedSpikes(duration, 5, 0.02)
+	ref := SpikeLine{
+		Label:  "ref",
+		Spikes: AddPoissonNoise(common, duration, 5, 0.02),
+	}
+	target := SpikeLine{
+		Label:  "target",
+		Spikes: AddPoissonNoise(common, duration, 5, 0.02),
+	}
+	c := Correlogram{
+		Title:     "Cross-correlogram",
+		Ref:       ref,
+		Target:    &target,
+		Rate:      true,
+		Predictor: JitterPredictor,
+	}
+	err := MakeCorrelogramPlot(c, "tests/correlogramPlot.png", "tests/correlogramPlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	c.Title = "Auto-correlogram"
+	c.Target = nil
+	c.Predictor = ShufflePredictor
+	err = MakeCorrelogramPlot(c, "tests/autoCorrelogramPlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+}
+
+func TestShuffleSurrogates(t *testing.T) {
+	ref := SpikeLine{Spikes: GeneratePoissonDistributedSpikes(100, 10, 0.002)}
+	c := Correlogram{
+		Ref:       ref,
+		Predictor: ShufflePredictor,
+		Surrogate: 1000,
+	}
+	mean, _ := c.Surrogates()
+	_, binWidth, _ := c.params()
+	// shifted surrogates are uniform in the recording window
+	n := float64(len(ref.Spikes))
+	duration := ref.Spikes[len(ref.Spikes)-1] - ref.Spikes[0]
+	expected := n * n * binWidth / duration
+	tolerance := 4*math.Sqrt(expected/float64(c.Surrogate)) + 0.01*expected
+	for i, m := range mean {
+		if math.Abs(m-expected) > tolerance {
+			t.Errorf("got surrogate mean %g in bin %d, expected %g ± %g", m, i, expected, tolerance)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package plots
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// DenseRaster is the drawing property of spike plots with many spike lines.
+// Spikes are drawn as dots or as ticks spanning the line height, without the
+// horizontal lines, extend bars, synchronous events or spike categories.
+// The spikes are rasterized into an image when their number exceeds the
+// threshold, which keeps SVG and PDF files small.
+type DenseRaster struct {
+	Dots       bool        // Draw spikes as dots instead of ticks.
+	Size       vg.Length   // Dot size or tick width (default = vg.Points(0.5)).
+	Color      color.Color // Spike color (default = line spike color).
+	MaxLabels  int         // Maximum number of line labels (default = 20).
+	Threshold  int         // Spike count above which spikes are rasterized (default = 100000).
+	Resolution float64     // Rasterized image resolution in dpi (default = 300).
+}
+
+// denseProperty returns the dense raster property with default values.
+func denseProperty(d *DenseRaster) DenseRaster {
+	p := *d
+	if p.Size == 0 {
+		p.Size = vg.Points(0.5)
+	}
+	if p.MaxLabels <= 0 {
+		p.MaxLabels = 20
+	}
+	if p.Threshold <= 0 {
+		p.Threshold = 100000
+	}
+	if p.Resolution <= 0 {
+		p.Resolution = 300
+	}
+	return p
+}
+
+// plotDense draws the spike lines as a dense raster.
+func (s SpikeLines) plotDense(canvas draw.Canvas, plt *plot.Plot) {
+	trX, trY := plt.Transforms(&canvas)
+	d := denseProperty(s.Dense)
+
+	// determine drawing order and spikes to draw
+	drawingOrder := make([]int, len(s.Lines))
+	for i := range drawingOrder {
+		drawingOrder[i] = i
+	}
+	sort.Slice(drawingOrder, func(i, j int) bool {
+		return s.Lines[drawingOrder[i]].ZIndex < s.Lines[drawingOrder[j]].ZIndex
+	})
+	spikes := make([][]float64, len(s.Lines))
+	var nSpikes int
+	for i := range s.Lines {
+		l := s.Lines[i].Spikes
+		beg := sort.SearchFloat64s(l, plt.X.Min)
+		end := sort.SearchFloat64s(l, plt.X.Max)
+		spikes[i] = l[beg:end]
+		nSpikes += end - beg
+	}
+	spikeColor := func(i int) color.Color {
+		switch {
+		case d.Color != nil:
+			return d.Color
+		case s.Lines[i].Property.Color != nil:
+			return s.Lines[i].Property.Color
+		case s.groupColor(s.Lines[i].Group) != nil:
+			return s.groupColor(s.Lines[i].Group)
+		}
+		return color.RGBA{0, 0, 0, 255}
+	}
+
+	// spike line height
+	dy := (plt.Y.Max - plt.Y.Min) / float64(len(s.Lines))
+
+	if nSpikes <= d.Threshold {
+		for _, i := range drawingOrder {
+			yMin := plt.Y.Min + dy*float64(len(s.Lines)-i-1)
+			yMinPx, yMaxPx := trY(yMin), trY(yMin+dy)
+			if d.Dots {
+				canvas.SetColor(spikeColor(i))
+				var path vg.Path
+				y := (yMinPx + yMaxPx) / 2
+				for _, v := range spikes[i] {
+					x := trX(v)
+					path.Move(vg.Point{X: x - d.Size/2, Y: y - d.Size/2})
+					path.Line(vg.Point{X: x + d.Size/2, Y: y - d.Size/2})
+					path.Line(vg.Point{X: x + d.Size/2, Y: y + d.Size/2})
+					path.Line(vg.Point{X: x - d.Size/2, Y: y + d.Size/2})
+					path.Close()
+				}
+				canvas.Fill(path)
+				continue
+			}
+			canvas.SetLineStyle(draw.LineStyle{
+				Color: spikeColor(i),
+				Width: d.Size,
+			})
+			var path vg.Path
+			for _, v := range spikes[i] {
+				x := trX(v)
+				path.Move(vg.Point{X: x, Y: yMinPx})
+				path.Line(vg.Point{X: x, Y: yMaxPx})
+			}
+			canvas.Stroke(path)
+		}
+		return
+	}
+
+	// rasterize the spikes in an image covering the data canvas
+	width := canvas.Max.X - canvas.Min.X
+	height := canvas.Max.Y - canvas.Min.Y
+	img := image.NewRGBA(image.Rect(0, 0,
+		int(math.Ceil(width.Dots(d.Resolution))),
+		int(math.Ceil(height.Dots(d.Resolution)))))
+	scale := d.Resolution / vg.Inch.Points()
+	size := max(int(math.Round(d.Size.Points()*scale)), 1)
+	for _, i := range drawingOrder {
+		c := spikeColor(i)
+		yMin := plt.Y.Min + dy*float64(len(s.Lines)-i-1)
+		pyBottom := int((canvas.Max.Y - trY(yMin)).Points() * scale)
+		pyTop := int((canvas.Max.Y - trY(yMin+dy)).Points() * scale)
+		if d.Dots {
+			pyTop = (pyTop+pyBottom)/2 - size/2
+			pyBottom = pyTop + size
+		}
+		pyBottom = max(pyBottom, pyTop+1)
+		for _, v := range spikes[i] {
+			px := int((trX(v)-canvas.Min.X).Points()*scale) - size/2
+			for y := pyTop; y < pyBottom; y++ {
+				for x := px; x < px+size; x++ {
+					img.Set(x, y, c)
+				}
+			}
+		}
+	}
+	canvas.DrawImage(canvas.Rectangle, img)
+}
+
+// denseTicks returns the line label ticks with at most MaxLabels labels.
+func (s SpikeLines) denseTicks(min, max float64) []plot.Tick {
+	return lineTicks(s.Lines, min, max, denseProperty(s.Dense).MaxLabels)
+}
+
+// lineTicks returns the ticks at the center of the lines drawn from top to
+// bottom in the range [min,max], with at most maxLabels labels.
+func lineTicks(lines []SpikeLine, min, max float64, maxLabels int) []plot.Tick {
+	step := (len(lines) + maxLabels - 1) / maxLabels
+	if step == 0 {
+		step = 1
+	}
+	dy := (max - min) / float64(len(lines))
+	ticks := make([]plot.Tick, 0, len(lines)/step+1)
+	for i := 0; i < len(lines); i += step {
+		ticks = append(ticks, plot.Tick{
+			Value: min + dy*float64(len(lines)-i-1) + dy/2,
+			Label: lines[i].Label,
+		})
+	}
+	return ticks
+}
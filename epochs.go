@@ -0,0 +1,60 @@
+package plots
+
+import (
+	"image/color"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Epoch is a labeled time interval, like a stimulus presentation, drawn as
+// a band behind the plot data over the whole plot height.
+type Epoch struct {
+	Start, End float64     // Epoch time interval.
+	Label      string      // Label drawn at the top of the band, none if empty.
+	Color      color.Color // Band fill color (default = grey).
+	Alpha      float64     // Band fill opacity in (0,1] (default = 0.3).
+}
+
+// Epochs is a set of epochs drawn as bands. The bands are clipped to the X
+// axis range of the plot and don't change it.
+type Epochs []Epoch
+
+// Plot draws the epoch bands and their labels.
+func (e Epochs) Plot(canvas draw.Canvas, plt *plot.Plot) {
+	trX, _ := plt.Transforms(&canvas)
+	textStyle := text.Style{
+		Color:   color.RGBA{0, 0, 0, 255},
+		Font:    font.From(plot.DefaultFont, vg.Points(10)),
+		XAlign:  draw.XCenter,
+		YAlign:  draw.YTop,
+		Handler: plt.TextHandler,
+	}
+	for _, epoch := range e {
+		start, end := max(epoch.Start, plt.X.Min), min(epoch.End, plt.X.Max)
+		if !(end > start) {
+			continue
+		}
+		fillColor := epoch.Color
+		if fillColor == nil {
+			fillColor = color.RGBA{160, 160, 160, 255}
+		}
+		alpha := epoch.Alpha
+		if alpha <= 0 || alpha > 1 {
+			alpha = 0.3
+		}
+		xMin, xMax := trX(start), trX(end)
+		rect := vg.Rectangle{
+			Min: vg.Point{X: xMin, Y: canvas.Min.Y},
+			Max: vg.Point{X: xMax, Y: canvas.Max.Y},
+		}
+		canvas.SetColor(withAlpha(fillColor, uint8(alpha*255)))
+		canvas.Fill(rect.Path())
+		if epoch.Label != "" {
+			canvas.FillText(textStyle, vg.Point{X: (xMin + xMax) / 2, Y: canvas.Max.Y}, epoch.Label)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package plots
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Figure is a grid of plots saved in a single file.
+type Figure struct {
+	Title   string    // Figure title, none if empty.
+	Rows    int       // Number of grid rows (default = panels extent).
+	Cols    int       // Number of grid columns (default = panels extent).
+	Panels  []Panel   // Panels of the figure.
+	SharedX bool      // Panels in the same columns share their X range and X axis labels.
+	SharedY bool      // Panels in the same rows share their Y range and Y axis labels, except spike plots.
+	Letters bool      // Draw the panel letters A, B, C... in the panel order.
+	PadX    vg.Length // Horizontal space between panels (default = vg.Centimeter).
+	PadY    vg.Length // Vertical space between panels (default = vg.Centimeter).
+	XDim    vg.Length // X dimension of saved figure, use default if 0.
+	YDim    vg.Length // Y dimension of saved figure, use default if 0.
+}
+
+// Panel is a plot in a figure spanning a range of grid cells. Only one of the
+// plots may be set.
+type Panel struct {
+	Row, Col         int         // Grid cell of the top left corner of the panel.
+	RowSpan, ColSpan int         // Number of rows and columns of the panel (default = 1).
+	Lines            *Lines      // Line plot.
+	Spikes           *SpikeLines // Spike plot.
+	Plot             *plot.Plot  // Custom plot, not changed by the shared axes.
+}
+
+// spans returns the row and column spans with default values.
+func (p Panel) spans() (rowSpan, colSpan int) {
+	return max(p.RowSpan, 1), max(p.ColSpan, 1)
+}
+
+// newPlot returns the plot of the panel and its axes.
+func (p Panel) newPlot() (panel, *plot.Plot, error) {
+	switch {
+	case p.Lines != nil:
+		plt, err := NewLinePlot(*p.Lines)
+		if err != nil {
+			return nil, nil, err
+		}
+		return plt, plt.Plot, nil
+	case p.Spikes != nil:
+		if err := p.Spikes.checkUnits(); err != nil {
+			return nil, nil, err
+		}
+		plt := NewSpikePlot(*p.Spikes)
+		return plt, plt.Plot, nil
+	case p.Plot != nil:
+		// shared axes change a copy of the custom plot
+		plt := *p.Plot
+		return &plt, &plt, nil
+	}
+	return nil, nil, errors.New("no plot")
+}
+
+// grid returns the number of rows and columns of the figure grid.
+func (f *Figure) grid() (rows, cols int) {
+	rows, cols = f.Rows, f.Cols
+	for _, p := range f.Panels {
+		rowSpan, colSpan := p.spans()
+		if f.Rows <= 0 {
+			rows = max(rows, p.Row+rowSpan)
+		}
+		if f.Cols <= 0 {
+			cols = max(cols, p.Col+colSpan)
+		}
+	}
+	return rows, cols
+}
+
+// figurePanel is a panel of the figure with its plot.
+type figurePanel struct {
+	Panel
+	drawer panel
+	plot   *plot.Plot
+	spikes bool
+	canvas draw.Canvas
+}
+
+// panels returns the plots of the panels with their shared axes.
+func (f *Figure) panels() ([]figurePanel, error) {
+	if len(f.Panels) == 0 {
+		return nil, errors.New("no panel")
+	}
+	rows, cols := f.grid()
+	panels := make([]figurePanel, len(f.Panels))
+	for i, p := range f.Panels {
+		rowSpan, colSpan := p.spans()
+		if p.Row < 0 || p.Col < 0 || p.Row+rowSpan > rows || p.Col+colSpan > cols {
+			return nil, fmt.Errorf("panel %d out of the %dx%d grid", i, rows, cols)
+		}
+		drawer, plt, err := p.newPlot()
+		if err != nil {
+			return nil, fmt.Errorf("panel %d: %w", i, err)
+		}
+		panels[i] = figurePanel{Panel: p, drawer: drawer, plot: plt, spikes: p.Spikes != nil}
+	}
+	if f.SharedX {
+		for _, group := range f.groups(panels, func(p *figurePanel) (int, int) { return p.Col, p.ColSpan }) {
+			xMin, xMax := math.Inf(1), math.Inf(-1)
+			bottom := group[0]
+			for _, p := range group {
+				xMin, xMax = min(xMin, p.plot.X.Min), max(xMax, p.plot.X.Max)
+				if p.Row > bottom.Row {
+					bottom = p
+				}
+			}
+			for _, p := range group {
+				p.plot.X.Min, p.plot.X.Max = xMin, xMax
+				if p != bottom {
+					p.plot.X.Label.Text = ""
+					p.plot.X.Tick.Marker = unlabeledTicks{Ticker: p.plot.X.Tick.Marker}
+				}
+			}
+		}
+	}
+	if f.SharedY {
+		for _, group := range f.groups(panels, func(p *figurePanel) (int, int) { return p.Row, p.RowSpan }) {
+			yMin, yMax := math.Inf(1), math.Inf(-1)
+			var left *figurePanel
+			for _, p := range group {
+				if p.spikes {
+					continue
+				}
+				yMin, yMax = min(yMin, p.plot.Y.Min), max(yMax, p.plot.Y.Max)
+				if left == nil || p.Col < left.Col {
+					left = p
+				}
+			}
+			for _, p := range group {
+				if p.spikes {
+					continue
+				}
+				p.plot.Y.Min, p.plot.Y.Max = yMin, yMax
+				if p != left {
+					p.plot.Y.Label.Text = ""
+					p.plot.Y.Tick.Marker = unlabeledTicks{Ticker: p.plot.Y.Tick.Marker}
+				}
+			}
+		}
+	}
+	return panels, nil
+}
+
+// groups returns the groups of panels with the same position and span
+// returned by key.
+func (f *Figure) groups(panels []figurePanel, key func(p *figurePanel) (pos, span int)) [][]*figurePanel {
+	var groups [][]*figurePanel
+	index := make(map[[2]int]int)
+	for i := range panels {
+		pos, span := key(&panels[i])
+		k := [2]int{pos, max(span, 1)}
+		g, ok := index[k]
+		if !ok {
+			g = len(groups)
+			index[k] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], &panels[i])
+	}
+	return groups
+}
+
+// draw draws the figure panels in dc.
+func (f *Figure) draw(panels []figurePanel, dc draw.Canvas) {
+	dc.SetColor(color.White)
+	dc.Fill(dc.Rectangle.Path())
+	if f.Title != "" {
+		dc = drawTitle(dc, f.Title)
+	}
+	rows, cols := f.grid()
+	tiles := draw.Tiles{
+		Rows: rows,
+		Cols: cols,
+		PadX: f.PadX,
+		PadY: f.PadY,
+	}
+	if tiles.PadX == 0 {
+		tiles.PadX = vg.Centimeter
+	}
+	if tiles.PadY == 0 {
+		tiles.PadY = vg.Centimeter
+	}
+	letterStyle := text.Style{
+		Color:   color.Black,
+		Font:    font.From(plot.DefaultFont, vg.Points(14)),
+		XAlign:  draw.XLeft,
+		YAlign:  draw.YTop,
+		Handler: plot.New().TextHandler,
+	}
+	for i := range panels {
+		p := &panels[i]
+		rowSpan, colSpan := p.spans()
+		topLeft := tiles.At(dc, p.Col, p.Row)
+		bottomRight := tiles.At(dc, p.Col+colSpan-1, p.Row+rowSpan-1)
+		c := topLeft
+		c.Max.X, c.Min.Y = bottomRight.Max.X, bottomRight.Min.Y
+		if f.Letters {
+			letter := string(rune('A' + i%26))
+			c.FillText(letterStyle, vg.Point{X: c.Min.X, Y: c.Max.Y}, letter)
+			c.Max.Y -= letterStyle.Height(letter)
+		}
+		p.canvas = c
+	}
+	if f.SharedX {
+		f.align(panels, func(p *figurePanel) (int, int) { return p.Col, p.ColSpan }, alignX)
+	}
+	if f.SharedY {
+		f.align(panels, func(p *figurePanel) (int, int) { return p.Row, p.RowSpan }, alignY)
+	}
+	for i := range panels {
+		panels[i].drawer.Draw(panels[i].canvas)
+	}
+}
+
+// align aligns the data areas of the groups of panels.
+func (f *Figure) align(panels []figurePanel, key func(p *figurePanel) (pos, span int), alignFn func([]panel, []draw.Canvas)) {
+	for _, group := range f.groups(panels, key) {
+		drawers := make([]panel, len(group))
+		canvases := make([]draw.Canvas, len(group))
+		for i, p := range group {
+			drawers[i], canvases[i] = p.drawer, p.canvas
+		}
+		alignFn(drawers, canvases)
+		for i, p := range group {
+			p.canvas = canvases[i]
+		}
+	}
+}
+
+// Save saves the figure in the files. The file format is determined by the
+// file name extension.
+func (f *Figure) Save(fileNames ...string) error {
+	panels, err := f.panels()
+	if err != nil {
+		return fmt.Errorf("figure: %w", err)
+	}
+	xDim, yDim := plotDims(f.XDim, f.YDim)
+	for _, fileName := range fileNames {
+		err := saveDrawing(xDim, yDim, fileName, func(dc draw.Canvas) {
+			f.draw(panels, dc)
+		})
+		if err != nil {
+			return fmt.Errorf("figure: %w", err)
+		}
+	}
+	return nil
+}
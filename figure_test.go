@@ -0,0 +1,84 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestFigure(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var spikes SpikeLines
+	for i := 0; i < 10; i++ {
+		spikes.Lines = append(spikes.Lines, SpikeLine{
+			Label:  fmt.Sprintf("%d", i+1),
+			Spikes: GeneratePoissonDistributedSpikes(4, 8, 0.02),
+		})
+	}
+	spikes.XLimit = &Limit{Min: 0, Max: 4}
+	var bins []float64
+	for _, b := range (PSTH{Lines: spikes.Lines, BinWidth: 0.1, Rate: true}).Bins(0, 4) {
+		bins = append(bins, b.Weight)
+	}
+	rates := make(plotter.XYs, len(bins))
+	for i, v := range bins {
+		rates[i] = plotter.XY{X: 0.05 + float64(i)*0.1, Y: v}
+	}
+	sin, cos := make(plotter.XYs, 100), make(plotter.XYs, 100)
+	for i := range sin {
+		x := float64(i) / 10
+		sin[i] = plotter.XY{X: x, Y: math.Sin(x)}
+		cos[i] = plotter.XY{X: x, Y: 2 * math.Cos(x)}
+	}
+	custom := plot.New()
+	custom.Title.Text = "Rate distribution"
+	hist, err := plotter.NewHist(plotter.Values(bins), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom.Add(hist)
+	fig := Figure{
+		Title:   "Figure",
+		SharedX: true,
+		SharedY: true,
+		Letters: true,
+		XDim:    20 * vg.Centimeter,
+		YDim:    15 * vg.Centimeter,
+		Panels: []Panel{
+			{Row: 0, Col: 0, RowSpan: 2, Spikes: &spikes},
+			{Row: 2, Col: 0, Lines: &Lines{YLabel: "Rate (Hz)", XLabel: "Time (s)", Lines: []Line{{Points: rates}}}},
+			{Row: 0, Col: 1, Lines: &Lines{Title: "sin", YLabel: "Y", Lines: []Line{{Points: sin}}}},
+			{Row: 0, Col: 2, Lines: &Lines{Title: "cos", Lines: []Line{{Points: cos, Color: DarkColors.Id(1)}}}},
+			{Row: 1, Col: 1, RowSpan: 2, ColSpan: 2, Plot: custom},
+		},
+	}
+	err = fig.Save("tests/figure.png", "tests/figure.svg", "tests/figure.pdf")
+	if err != nil {
+		t.Fatalf("failed saving figure: %s", err)
+	}
+	shared := Figure{
+		SharedX: true,
+		Panels: []Panel{
+			{Row: 0, Col: 0, Lines: &Lines{XLabel: "X", Lines: []Line{{Points: sin}}}},
+			{Row: 1, Col: 0, Plot: custom},
+		},
+	}
+	x := custom.X
+	for i := 0; i < 2; i++ {
+		if err := shared.Save("tests/sharedFigure.png"); err != nil {
+			t.Fatalf("failed saving figure: %s", err)
+		}
+	}
+	if custom.X.Min != x.Min || custom.X.Max != x.Max || custom.X.Label.Text != x.Label.Text || custom.X.Tick.Marker != x.Tick.Marker {
+		t.Errorf("got custom plot X axis [%g,%g] changed by the figure, want [%g,%g]", custom.X.Min, custom.X.Max, x.Min, x.Max)
+	}
+	fig.Rows = 2
+	if err := fig.Save("tests/figure.png"); err == nil {
+		t.Fatalf("expected error with panel out of grid")
+	}
+}
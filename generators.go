@@ -0,0 +1,207 @@
+package plots
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Generator generates random spike lines. The spike times are in [0,duration)
+// and sorted in increasing order.
+type Generator struct {
+	Rand       *rand.Rand // Random source (default = fixed seed).
+	Refractory float64    // Minimum interval between spikes (default = 0).
+}
+
+// rng returns the random source, and sets it to the default one if nil.
+func (g *Generator) rng() *rand.Rand {
+	if g.Rand == nil {
+		g.Rand = rand.New(rand.NewPCG(1, 2))
+	}
+	return g.Rand
+}
+
+// renewal returns the spike line of the renewal process with the given
+// interval generator. The refractory period is added to the intervals
+// following a spike.
+func (g *Generator) renewal(duration float64, interval func() float64) SpikeLine {
+	var l SpikeLine
+	t := interval()
+	for t < duration {
+		l.Spikes = append(l.Spikes, t)
+		t += g.Refractory + interval()
+	}
+	return l
+}
+
+// Poisson returns a spike line of a homogeneous Poisson process with the
+// given rate in Hz. With a refractory period, the intervals are the
+// refractory period plus exponentially distributed intervals.
+func (g *Generator) Poisson(rate, duration float64) SpikeLine {
+	if rate <= 0 || duration <= 0 {
+		return SpikeLine{}
+	}
+	rng := g.rng()
+	return g.renewal(duration, func() float64 {
+		return rng.ExpFloat64() / rate
+	})
+}
+
+// InhomogeneousPoisson returns a spike line of a Poisson process whose rate
+// in Hz at time t is rate(t). The rate function must not exceed maxRate.
+// The spikes are generated by thinning a homogeneous Poisson process of rate
+// maxRate. With a refractory period, spikes closer than the refractory
+// period to the previous spike are discarded.
+func (g *Generator) InhomogeneousPoisson(rate func(t float64) float64, maxRate, duration float64) SpikeLine {
+	var l SpikeLine
+	if maxRate <= 0 || duration <= 0 {
+		return l
+	}
+	rng := g.rng()
+	last := math.Inf(-1)
+	for t := rng.ExpFloat64() / maxRate; t < duration; t += rng.ExpFloat64() / maxRate {
+		if rng.Float64()*maxRate >= rate(t) || t-last < g.Refractory {
+			continue
+		}
+		l.Spikes = append(l.Spikes, t)
+		last = t
+	}
+	return l
+}
+
+// Gamma returns a spike line of a Gamma renewal process with the given rate
+// in Hz and shape. The shape 1 yields a Poisson process, and greater shapes
+// more regular spikes with an interval coefficient of variation of
+// 1/sqrt(shape). With a refractory period, the intervals are the refractory
+// period plus Gamma distributed intervals.
+func (g *Generator) Gamma(rate, shape, duration float64) SpikeLine {
+	if rate <= 0 || shape <= 0 || duration <= 0 {
+		return SpikeLine{}
+	}
+	rng := g.rng()
+	return g.renewal(duration, func() float64 {
+		return gammaFloat64(rng, shape) / (shape * rate)
+	})
+}
+
+// gammaFloat64 returns a Gamma distributed value with the given shape and a
+// scale of 1, using the Marsaglia and Tsang method.
+func gammaFloat64(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return gammaFloat64(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+	d := shape - 1./3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < x*x/2+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// Jitter returns a copy of the spike line with each spike shifted by a
+// normally distributed value with standard deviation sigma. The spikes keep
+// their category. With a refractory period, spikes closer than the
+// refractory period to the previous spike are discarded.
+func (g *Generator) Jitter(l SpikeLine, sigma float64) SpikeLine {
+	rng := g.rng()
+	spikes := make([]float64, len(l.Spikes))
+	order := make([]int, len(l.Spikes))
+	for i, v := range l.Spikes {
+		spikes[i] = v + sigma*rng.NormFloat64()
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int { return cmp.Compare(spikes[i], spikes[j]) })
+	res := make([]float64, 0, len(spikes))
+	var categories []int
+	if l.Categories != nil {
+		categories = make([]int, 0, len(spikes))
+	}
+	for _, i := range order {
+		if g.Refractory > 0 && len(res) != 0 && spikes[i]-res[len(res)-1] < g.Refractory {
+			continue
+		}
+		res = append(res, spikes[i])
+		if categories != nil {
+			categories = append(categories, l.Category(i))
+		}
+	}
+	l.Spikes, l.Categories = res, categories
+	return l
+}
+
+// enforceRefractory removes in place the spikes closer than the refractory
+// period to the previous spike.
+func (g *Generator) enforceRefractory(spikes []float64) []float64 {
+	if g.Refractory <= 0 || len(spikes) == 0 {
+		return spikes
+	}
+	res := spikes[:1]
+	for _, v := range spikes[1:] {
+		if v-res[len(res)-1] >= g.Refractory {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Embed returns a copy of the spike lines where a spike is inserted in each
+// of the member lines at each pattern time, shifted by a normally distributed
+// value with standard deviation jitter. The spikes of the lines closer than
+// the refractory period to an inserted spike are removed. The inserted
+// spikes are in the category pattern when it is not negative, and the other
+// spikes keep their category.
+func (g *Generator) Embed(lines []SpikeLine, members []int, times []float64, jitter float64, pattern int) []SpikeLine {
+	rng := g.rng()
+	res := slices.Clone(lines)
+	for _, m := range members {
+		l := &res[m]
+		inserted := make([]float64, len(times))
+		for i, t := range times {
+			inserted[i] = t + jitter*rng.NormFloat64()
+		}
+		slices.Sort(inserted)
+		inserted = g.enforceRefractory(inserted)
+
+		// merge the line spikes with the inserted spikes
+		spikes := make([]float64, 0, len(l.Spikes)+len(inserted))
+		var categories []int
+		if l.Categories != nil || pattern >= 0 {
+			categories = make([]int, 0, cap(spikes))
+		}
+		var k int
+		for i, v := range l.Spikes {
+			for k < len(inserted) && inserted[k] <= v {
+				spikes = append(spikes, inserted[k])
+				if categories != nil {
+					categories = append(categories, pattern)
+				}
+				k++
+			}
+			if k > 0 && v-inserted[k-1] < g.Refractory ||
+				k < len(inserted) && inserted[k]-v < g.Refractory {
+				continue
+			}
+			spikes = append(spikes, v)
+			if categories != nil {
+				categories = append(categories, l.Category(i))
+			}
+		}
+		for ; k < len(inserted); k++ {
+			spikes = append(spikes, inserted[k])
+			if categories != nil {
+				categories = append(categories, pattern)
+			}
+		}
+		l.Spikes, l.Categories = spikes, categories
+	}
+	return res
+}
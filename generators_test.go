@@ -0,0 +1,136 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"testing"
+
+	"gonum.org/v1/plot/vg"
+)
+
+func TestGeneratorPoisson(t *testing.T) {
+	g := Generator{Rand: rand.New(rand.NewPCG(3, 4))}
+	l := g.Poisson(100, 100)
+	if n := len(l.Spikes); math.Abs(float64(n)-10000) > 300 {
+		t.Errorf("got %d spikes, expected about 10000", n)
+	}
+	if !slices.IsSorted(l.Spikes) || l.Spikes[len(l.Spikes)-1] >= 100 {
+		t.Errorf("spikes are not sorted or out of range")
+	}
+	g.Refractory = 0.005
+	l = g.Poisson(100, 100)
+	if m := slices.Min(Intervals(l.Spikes)); m < g.Refractory {
+		t.Errorf("got interval %v smaller than refractory period", m)
+	}
+	if n := len(g.Poisson(0, 10).Spikes); n != 0 {
+		t.Errorf("got %d spikes with null rate, expected 0", n)
+	}
+}
+
+func TestGeneratorInhomogeneousPoisson(t *testing.T) {
+	var g Generator
+	rate := func(t float64) float64 {
+		if t < 50 {
+			return 20
+		}
+		return 100
+	}
+	l := g.InhomogeneousPoisson(rate, 100, 100)
+	k, _ := slices.BinarySearch(l.Spikes, 50)
+	if n := float64(k); math.Abs(n-1000) > 100 {
+		t.Errorf("got %v spikes in first half, expected about 1000", n)
+	}
+	if n := float64(len(l.Spikes) - k); math.Abs(n-5000) > 250 {
+		t.Errorf("got %v spikes in second half, expected about 5000", n)
+	}
+}
+
+func TestGeneratorGamma(t *testing.T) {
+	var g Generator
+	for _, shape := range []float64{0.5, 1, 4} {
+		l := g.Gamma(50, shape, 200)
+		if n := float64(len(l.Spikes)); math.Abs(n-10000) > 500 {
+			t.Errorf("got %v spikes with shape %v, expected about 10000", n, shape)
+		}
+		cv, expected := CV(Intervals(l.Spikes)), 1/math.Sqrt(shape)
+		if math.Abs(cv-expected) > 0.1*expected {
+			t.Errorf("got CV %v with shape %v, expected about %v", cv, shape, expected)
+		}
+	}
+}
+
+func TestGeneratorEmbed(t *testing.T) {
+	g := Generator{Refractory: 0.01}
+	lines := []SpikeLine{g.Poisson(10, 1), g.Poisson(10, 1), g.Poisson(10, 1)}
+	times := []float64{0.25, 0.5, 0.75}
+	res := g.Embed(lines, []int{0, 2}, times, 0, 1)
+	if len(res[1].Spikes) != len(lines[1].Spikes) || res[1].Categories != nil {
+		t.Errorf("non member line was modified")
+	}
+	for _, i := range []int{0, 2} {
+		l := &res[i]
+		if !slices.IsSorted(l.Spikes) || len(l.Categories) != len(l.Spikes) {
+			t.Fatalf("invalid line %d", i)
+		}
+		var n int
+		for k, v := range l.Spikes {
+			if l.Category(k) == 1 {
+				n++
+				if !slices.Contains(times, v) {
+					t.Errorf("got pattern spike at %v", v)
+				}
+			}
+		}
+		if n != len(times) {
+			t.Errorf("got %d pattern spikes in line %d, expected %d", n, i, len(times))
+		}
+		if m := slices.Min(Intervals(l.Spikes)); m < g.Refractory {
+			t.Errorf("got interval %v smaller than refractory period", m)
+		}
+	}
+	jittered := g.Jitter(res[0], 0.002)
+	if !slices.IsSorted(jittered.Spikes) || len(jittered.Categories) != len(jittered.Spikes) {
+		t.Errorf("invalid jittered line")
+	}
+	categorized := SpikeLine{Spikes: []float64{0.1, 0.1005, 0.2, 0.3}, Categories: []int{0, 1, 2}}
+	jittered = (&Generator{}).Jitter(categorized, 0.0001)
+	if fmt.Sprint(jittered.Categories) != "[0 1 2 -1]" {
+		t.Errorf("got categories %v, expected [0 1 2 -1]", jittered.Categories)
+	}
+	jittered = (&Generator{Refractory: 0.01}).Jitter(categorized, 0.0001)
+	if len(jittered.Categories) != len(jittered.Spikes) || jittered.Categories[len(jittered.Categories)-1] != -1 {
+		t.Errorf("got categories %v for spikes %v", jittered.Categories, jittered.Spikes)
+	}
+}
+
+func TestGeneratorSpikePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	g := Generator{Refractory: 0.005}
+	var spikes SpikeLines
+	for i := 0; i < 20; i++ {
+		var l SpikeLine
+		switch i % 3 {
+		case 0:
+			l = g.Poisson(10, 2)
+		case 1:
+			l = g.Gamma(10, 8, 2)
+		default:
+			l = g.InhomogeneousPoisson(func(t float64) float64 {
+				return 20 * (1 + math.Sin(2*math.Pi*t))
+			}, 40, 2)
+		}
+		l.Label = fmt.Sprintf("%d", i+1)
+		spikes.Lines = append(spikes.Lines, l)
+	}
+	pattern := g.Poisson(2, 2).Spikes
+	spikes.Lines = g.Embed(spikes.Lines, []int{1, 4, 7, 10, 13}, pattern, 0.002, 0)
+	spikes.Title = "Generated spikes"
+	spikes.Categories = []SpikeCategory{{Label: "pattern", Color: DarkColors.Id(1), Width: vg.Points(2)}}
+	err := MakeSpikePlot(spikes, "tests/generatorSpikePlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+}
@@ -0,0 +1,343 @@
+package plots
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Order is a spike line ordering strategy. It returns the permutation of
+// the line indexes in the order the lines are drawn from top to bottom.
+type Order func(lines []SpikeLine) []int
+
+// identity returns the identity permutation of n indexes.
+func identity(n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	return perm
+}
+
+// ByLabel orders the lines by increasing label. The number labels come
+// first ordered by value, followed by the other labels in lexical order.
+func ByLabel(lines []SpikeLine) []int {
+	values := make([]float64, len(lines))
+	numbers := make([]bool, len(lines))
+	for i := range lines {
+		v, err := strconv.ParseFloat(lines[i].Label, 64)
+		values[i], numbers[i] = v, err == nil && !math.IsNaN(v)
+	}
+	perm := identity(len(lines))
+	sort.SliceStable(perm, func(i, j int) bool {
+		a, b := perm[i], perm[j]
+		switch {
+		case numbers[a] && numbers[b]:
+			return values[a] < values[b]
+		case numbers[a] != numbers[b]:
+			return numbers[a]
+		}
+		return lines[a].Label < lines[b].Label
+	})
+	return perm
+}
+
+// ByRate orders the lines by decreasing mean firing rate.
+func ByRate(lines []SpikeLine) []int {
+	perm := identity(len(lines))
+	sort.SliceStable(perm, func(i, j int) bool {
+		return len(lines[perm[i]].Spikes) > len(lines[perm[j]].Spikes)
+	})
+	return perm
+}
+
+// ByLatency orders the lines by increasing latency of the first spike at or
+// after t0. Lines without such spike are last.
+func ByLatency(t0 float64) Order {
+	return func(lines []SpikeLine) []int {
+		latency := make([]float64, len(lines))
+		for i := range lines {
+			latency[i] = math.Inf(1)
+			if k := sort.SearchFloat64s(lines[i].Spikes, t0); k < len(lines[i].Spikes) {
+				latency[i] = lines[i].Spikes[k] - t0
+			}
+		}
+		perm := identity(len(lines))
+		sort.SliceStable(perm, func(i, j int) bool {
+			return latency[perm[i]] < latency[perm[j]]
+		})
+		return perm
+	}
+}
+
+// ByPermutation orders the lines by the given permutation of line indexes.
+// Indexes out of range and repeated indexes are ignored, and the lines
+// missing from the permutation are last in their original order.
+func ByPermutation(perm []int) Order {
+	return func(lines []SpikeLine) []int {
+		return completePermutation(perm, len(lines))
+	}
+}
+
+// completePermutation returns the permutation of n indexes made of the valid
+// indexes of perm, ignoring indexes out of range and repeated indexes,
+// followed by the missing indexes in increasing order.
+func completePermutation(perm []int, n int) []int {
+	res := make([]int, 0, n)
+	used := make([]bool, n)
+	for _, i := range perm {
+		if i >= 0 && i < n && !used[i] {
+			used[i] = true
+			res = append(res, i)
+		}
+	}
+	for i := range used {
+		if !used[i] {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// BySimilarity orders the lines by hierarchical clustering of their spike
+// trains so that similar lines are next to each other. The similarity is the
+// correlation of the spike counts in bins of the given width, and clusters
+// are merged with average linkage. The computation time grows with the cube
+// of the number of lines.
+func BySimilarity(binWidth float64) Order {
+	return func(lines []SpikeLine) []int {
+		n := len(lines)
+		xMin, xMax := spikesRange(lines)
+		if n < 3 || !(xMax >= xMin) || binWidth <= 0 {
+			return identity(n)
+		}
+		nBins := int((xMax-xMin)/binWidth) + 1
+		vectors := Rates(lines, xMin, xMin+float64(nBins)*binWidth, nBins)
+		for _, v := range vectors {
+			var mean, norm float64
+			for _, x := range v {
+				mean += x
+			}
+			mean /= float64(len(v))
+			for i := range v {
+				v[i] -= mean
+				norm += v[i] * v[i]
+			}
+			if norm > 0 {
+				norm = math.Sqrt(norm)
+				for i := range v {
+					v[i] /= norm
+				}
+			}
+		}
+
+		// distances between clusters, initially one per line
+		dist := make([][]float64, n)
+		for i := range dist {
+			dist[i] = make([]float64, n)
+			for j := range i {
+				var corr float64
+				for k := range vectors[i] {
+					corr += vectors[i][k] * vectors[j][k]
+				}
+				dist[i][j] = 1 - corr
+				dist[j][i] = dist[i][j]
+			}
+		}
+		members := make([][]int, n)
+		for i := range members {
+			members[i] = []int{i}
+		}
+		active := identity(n)
+		for len(active) > 1 {
+			a, b := 0, 1
+			for i := range active {
+				for j := i + 1; j < len(active); j++ {
+					if dist[active[i]][active[j]] < dist[active[a]][active[b]] {
+						a, b = i, j
+					}
+				}
+			}
+			ca, cb := active[a], active[b]
+			na, nb := float64(len(members[ca])), float64(len(members[cb]))
+			for _, k := range active {
+				d := (na*dist[ca][k] + nb*dist[cb][k]) / (na + nb)
+				dist[ca][k], dist[k][ca] = d, d
+			}
+			members[ca] = append(members[ca], members[cb]...)
+			active = append(active[:b], active[b+1:]...)
+		}
+		return members[active[0]]
+	}
+}
+
+// Sorted returns a copy of the spike lines with the lines sorted by Order,
+// and then grouped by population group in the order of Groups. Lines whose
+// group is not in Groups are last.
+func (s SpikeLines) Sorted() SpikeLines {
+	if s.Order == nil && s.Groups == nil {
+		return s
+	}
+	perm := identity(len(s.Lines))
+	if s.Order != nil {
+		perm = completePermutation(s.Order(s.Lines), len(s.Lines))
+	}
+	if s.Groups != nil {
+		rank := func(i int) int {
+			if g := s.groupIndex(s.Lines[i].Group); g >= 0 {
+				return g
+			}
+			return len(s.Groups)
+		}
+		sort.SliceStable(perm, func(i, j int) bool {
+			return rank(perm[i]) < rank(perm[j])
+		})
+	}
+	lines := make([]SpikeLine, len(perm))
+	for i, j := range perm {
+		lines[i] = s.Lines[j]
+	}
+	s.Lines = lines
+	return s
+}
+
+// groupIndex returns the index of the group name in Groups, or -1 if
+// not found.
+func (s SpikeLines) groupIndex(name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, g := range s.Groups {
+		if g == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// groupColor returns the default spike color of the group, or nil if none.
+func (s SpikeLines) groupColor(name string) color.Color {
+	g := s.groupIndex(name)
+	if g < 0 || s.GroupColors == nil {
+		return nil
+	}
+	return s.GroupColors.Id(g)
+}
+
+// groupRun is a sequence of consecutive lines of a group.
+type groupRun struct {
+	name        string
+	first, last int
+}
+
+// groupRuns returns the sequences of consecutive lines of a same group
+// in Groups, or nil if no line is in one of the Groups.
+func (s SpikeLines) groupRuns() []groupRun {
+	var runs []groupRun
+	var grouped bool
+	for i := range s.Lines {
+		name := s.Lines[i].Group
+		if s.groupIndex(name) < 0 {
+			name = ""
+		} else {
+			grouped = true
+		}
+		if len(runs) != 0 && runs[len(runs)-1].name == name {
+			runs[len(runs)-1].last = i
+			continue
+		}
+		runs = append(runs, groupRun{name: name, first: i, last: i})
+	}
+	if !grouped {
+		return nil
+	}
+	return runs
+}
+
+// drawSeparators draws a horizontal line between groups.
+func (s SpikeLines) drawSeparators(canvas draw.Canvas, plt *plot.Plot) {
+	runs := s.groupRuns()
+	if len(runs) < 2 {
+		return
+	}
+	trX, trY := plt.Transforms(&canvas)
+	dy := (plt.Y.Max - plt.Y.Min) / float64(len(s.Lines))
+	canvas.SetLineStyle(draw.LineStyle{
+		Color:  color.RGBA{160, 160, 160, 255},
+		Width:  vg.Points(1),
+		Dashes: Dashes.Id(1),
+	})
+	for _, r := range runs[:len(runs)-1] {
+		y := trY(plt.Y.Min + dy*float64(len(s.Lines)-r.last-1) - dy/4)
+		var path vg.Path
+		path.Move(vg.Point{X: trX(plt.X.Min), Y: y})
+		path.Line(vg.Point{X: trX(plt.X.Max), Y: y})
+		canvas.Stroke(path)
+	}
+}
+
+// groupsWidth returns the width of the group brackets and names, or 0 if
+// there are no groups.
+func (p *SpikePlot) groupsWidth() vg.Length {
+	if p.lines.groupRuns() == nil {
+		return 0
+	}
+	sty := p.Y.Label.TextStyle
+	return sty.FontExtents().Height + sty.FontExtents().Descent + vg.Points(8)
+}
+
+// DataCanvas returns the data area of the plot drawn in c.
+func (p *SpikePlot) DataCanvas(c draw.Canvas) draw.Canvas {
+	return p.Plot.DataCanvas(draw.Crop(c, p.groupsWidth(), 0, 0, 0))
+}
+
+// Draw draws the plot and the group brackets and names in c.
+func (p *SpikePlot) Draw(c draw.Canvas) {
+	width := p.groupsWidth()
+	if width == 0 {
+		p.Plot.Draw(c)
+		return
+	}
+	c.SetColor(p.BackgroundColor)
+	c.Fill(c.Rectangle.Path())
+	pc := draw.Crop(c, width, 0, 0, 0)
+	p.Plot.Draw(pc)
+
+	dataC := p.Plot.DataCanvas(pc)
+	_, trY := p.Transforms(&dataC)
+	n := len(p.lines.Lines)
+	dy := (p.Y.Max - p.Y.Min) / float64(n)
+	sty := p.Y.Label.TextStyle
+	sty.Rotation += math.Pi / 2
+	sty.XAlign, sty.YAlign = draw.XCenter, draw.YTop
+	xBracket := c.Min.X + width - vg.Points(4)
+	lineStyle := draw.LineStyle{
+		Color: color.RGBA{0, 0, 0, 255},
+		Width: vg.Points(1),
+	}
+	for _, r := range p.lines.groupRuns() {
+		if r.name == "" {
+			continue
+		}
+		yBottom := trY(p.Y.Min + dy*float64(n-r.last-1))
+		yTop := trY(p.Y.Min + dy*float64(n-r.first-1) + dy/2)
+		c.StrokeLines(lineStyle, []vg.Point{
+			{X: xBracket + vg.Points(3), Y: yTop},
+			{X: xBracket, Y: yTop},
+			{X: xBracket, Y: yBottom},
+			{X: xBracket + vg.Points(3), Y: yBottom},
+		})
+		c.FillText(sty, vg.Point{X: c.Min.X, Y: (yTop + yBottom) / 2}, r.name)
+	}
+}
+
+// Save saves the plot of the given size in the file with the group brackets
+// and names. The file format is determined by the file name extension.
+func (p *SpikePlot) Save(xDim, yDim vg.Length, fileName string) error {
+	return saveDrawing(xDim, yDim, fileName, p.Draw)
+}
@@ -0,0 +1,187 @@
+package plots
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/palette/moreland"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// RateHeatmap is the property of a heatmap of the firing rates of spike
+// lines in time bins.
+type RateHeatmap struct {
+	BinWidth   float64          // Time bin width (default = time range / 100).
+	ColorMap   palette.ColorMap // Rate color map, its range is not changed (default = moreland.ExtendedBlackBody()).
+	Max        float64          // Maximum rate of the color scale, use max rate if 0.
+	MaxLabels  int              // Maximum number of line labels (default = 20).
+	Rasterized bool             // Draw the heatmap as an image.
+}
+
+// rateGrid is the firing rates of lines in time bins. It implements the
+// plotter.GridXYZ interface with the first line in the top row.
+type rateGrid struct {
+	rates    [][]float64
+	xMin     float64
+	binWidth float64
+}
+
+func (g rateGrid) Dims() (c, r int) {
+	if len(g.rates) == 0 {
+		return 0, 0
+	}
+	return len(g.rates[0]), len(g.rates)
+}
+
+func (g rateGrid) Z(c, r int) float64 { return g.rates[len(g.rates)-r-1][c] }
+func (g rateGrid) X(c int) float64    { return g.xMin + (float64(c)+0.5)*g.binWidth }
+func (g rateGrid) Y(r int) float64    { return float64(r) }
+
+// rateColorMap is a color map of the rates in [0,max] with the palette of
+// the embedded color map, whose range is left unchanged.
+type rateColorMap struct {
+	palette.ColorMap
+	palette palette.Palette
+	max     float64
+}
+
+// newRateColorMap returns the color map of the rates in [0,max] with the
+// colors of m.
+func newRateColorMap(m palette.ColorMap, max float64) rateColorMap {
+	return rateColorMap{ColorMap: m, palette: m.Palette(255), max: max}
+}
+
+// At returns the palette color of the rate v.
+func (m rateColorMap) At(v float64) (color.Color, error) {
+	switch {
+	case v < 0:
+		return nil, palette.ErrUnderflow
+	case v > m.max:
+		return nil, palette.ErrOverflow
+	}
+	colors := m.palette.Colors()
+	return colors[int(math.Round(v/m.max*float64(len(colors)-1)))], nil
+}
+
+func (m rateColorMap) Min() float64 { return 0 }
+func (m rateColorMap) Max() float64 { return m.max }
+
+// Rates returns the firing rates in Hz of the lines in nBins time bins of
+// equal width covering the range [xMin,xMax).
+func Rates(lines []SpikeLine, xMin, xMax float64, nBins int) [][]float64 {
+	binWidth := (xMax - xMin) / float64(nBins)
+	rates := make([][]float64, len(lines))
+	for i := range lines {
+		rates[i] = make([]float64, nBins)
+		spikes := lines[i].Spikes
+		beg := sort.SearchFloat64s(spikes, xMin)
+		end := sort.SearchFloat64s(spikes, xMax)
+		for _, v := range spikes[beg:end] {
+			rates[i][min(int((v-xMin)/binWidth), nBins-1)] += 1 / binWidth
+		}
+	}
+	return rates
+}
+
+// lineTicker is a plot.Ticker of the line labels.
+type lineTicker struct {
+	lines     []SpikeLine
+	maxLabels int
+}
+
+func (t lineTicker) Ticks(min, max float64) []plot.Tick {
+	return lineTicks(t.lines, min, max, t.maxLabels)
+}
+
+// newRateHeatmapPlots returns the rate heatmap plot of the spike lines and
+// its color bar plot.
+func newRateHeatmapPlots(spikeLines SpikeLines, h RateHeatmap) (*plot.Plot, *plot.Plot, error) {
+	if err := spikeLines.checkUnits(); err != nil {
+		return nil, nil, err
+	}
+	spikeLines = spikeLines.converted().Sorted()
+	xMin, xMax := spikeLines.xRange()
+	if !(xMax > xMin) || math.IsInf(xMax-xMin, 0) || len(spikeLines.Lines) == 0 {
+		return nil, nil, fmt.Errorf("empty time range or no spike lines")
+	}
+	nBins := 100
+	if h.BinWidth > 0 {
+		nBins = max(int(math.Round((xMax-xMin)/h.BinWidth)), 1)
+	}
+	grid := rateGrid{
+		rates:    Rates(spikeLines.Lines, xMin, xMax, nBins),
+		xMin:     xMin,
+		binWidth: (xMax - xMin) / float64(nBins),
+	}
+	if perSecond := spikeLines.Unit.perSecond(); perSecond != 1 {
+		for i := range grid.rates {
+			for j := range grid.rates[i] {
+				grid.rates[i][j] *= perSecond
+			}
+		}
+	}
+	maxRate := h.Max
+	if maxRate <= 0 {
+		for i := range grid.rates {
+			for _, v := range grid.rates[i] {
+				maxRate = max(maxRate, v)
+			}
+		}
+		if maxRate == 0 {
+			maxRate = 1
+		}
+	}
+	colorMap := h.ColorMap
+	if colorMap == nil {
+		colorMap = moreland.ExtendedBlackBody()
+	}
+	rates := newRateColorMap(colorMap, maxRate)
+	maxLabels := h.MaxLabels
+	if maxLabels <= 0 {
+		maxLabels = 20
+	}
+
+	p := plot.New()
+	p.Title.Text = spikeLines.Title
+	p.X.Label.Text = spikeLines.xLabel()
+	p.X.Tick.Marker = timeTicks{unit: spikeLines.Unit}
+	heatMap := plotter.NewHeatMap(grid, rates.palette)
+	heatMap.Min, heatMap.Max = 0, maxRate
+	heatMap.Overflow = heatMap.Palette.Colors()[254]
+	heatMap.Rasterized = h.Rasterized
+	p.Add(heatMap)
+	p.Y.Tick.Marker = lineTicker{lines: spikeLines.Lines, maxLabels: maxLabels}
+
+	bar := plot.New()
+	bar.HideX()
+	bar.Y.Label.Text = spikeLines.Unit.rateLabel()
+	bar.Add(&plotter.ColorBar{ColorMap: rates, Vertical: true})
+	return p, bar, nil
+}
+
+// MakeRateHeatmapPlot generates the firing rate heatmap of the spike lines
+// with a color bar of the rate scale. The rows are the spike lines from top
+// to bottom as in the spike plot. The time range is XLimit, or the spike time
+// range when nil. The bin width is adjusted to have an integer number of bins
+// in the time range.
+func MakeRateHeatmapPlot(spikeLines SpikeLines, h RateHeatmap, fileNames ...string) error {
+	p, bar, err := newRateHeatmapPlots(spikeLines, h)
+	if err != nil {
+		return fmt.Errorf("rate heatmap plot: %w", err)
+	}
+	xDim, yDim := plotDims(spikeLines.XDim, spikeLines.YDim)
+	for _, fileName := range fileNames {
+		err := saveDrawing(xDim, yDim, fileName, func(dc draw.Canvas) {
+			drawWithColorBar(p, bar, dc)
+		})
+		if err != nil {
+			return fmt.Errorf("rate heatmap plot: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package plots
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/palette/moreland"
+)
+
+func TestRates(t *testing.T) {
+	lines := []SpikeLine{
+		{Spikes: []float64{0.1, 0.2, 0.6, 1}},
+	}
+	rates := Rates(lines, 0, 1, 2)
+	if fmt.Sprint(rates) != "[[4 2]]" {
+		t.Fatalf("got rates %v, expected [[4 2]]", rates)
+	}
+}
+
+func TestRateHeatmapPlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var spikes SpikeLines
+	for i := 0; i < 100; i++ {
+		spikes.Lines = append(spikes.Lines, SpikeLine{
+			Label:  fmt.Sprintf("%d", i+1),
+			Spikes: GeneratePoissonDistributedSpikes(4, float64(1+i/5), 0.02),
+		})
+	}
+	spikes.Title = "Firing rates"
+	spikes.XLimit = &Limit{Min: 0, Max: 4}
+	err := MakeRateHeatmapPlot(spikes, RateHeatmap{BinWidth: 0.2}, "tests/rateHeatmapPlot.png", "tests/rateHeatmapPlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	colorMap := moreland.SmoothBlueRed()
+	colorMap.SetMax(1)
+	err = MakeRateHeatmapPlot(spikes, RateHeatmap{ColorMap: colorMap, Rasterized: true, MaxLabels: 5}, "tests/rateHeatmapRasterPlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	if colorMap.Min() != 0 || colorMap.Max() != 1 {
+		t.Errorf("got color map range [%g,%g] changed, want [0,1]", colorMap.Min(), colorMap.Max())
+	}
+}
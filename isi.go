@@ -0,0 +1,312 @@
+package plots
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// ISI is an inter-spike interval histogram of spike lines.
+type ISI struct {
+	Title     string      // Title.
+	Lines     []SpikeLine // Spike lines whose intervals are histogrammed.
+	Select    []int       // Index of the lines to use, all lines if nil.
+	Pooled    bool        // Pool the intervals of all lines in one histogram.
+	Bins      int         // Number of bins (default = 50).
+	LogBins   bool        // Use logarithmically spaced bins and log X axis.
+	MaxISI    float64     // Maximum interval, use max interval if 0.
+	ReturnMap bool        // Draw the ISI(n+1) vs ISI(n) return map beside.
+	Unit      TimeUnit    // Spike time unit (default = Seconds).
+	Colors    ColorTable  // Line colors (default = DarkColors).
+	XDim      vg.Length   // X dimension of saved plot, use default if 0.
+	YDim      vg.Length   // Y dimension of saved plot, use default if 0.
+}
+
+// Intervals returns the inter-spike intervals of the sorted spike times.
+func Intervals(spikes []float64) []float64 {
+	if len(spikes) < 2 {
+		return nil
+	}
+	res := make([]float64, len(spikes)-1)
+	for i := range res {
+		res[i] = spikes[i+1] - spikes[i]
+	}
+	return res
+}
+
+// CV returns the coefficient of variation of the intervals, the standard
+// deviation divided by the mean. Returns NaN when there are less than
+// two intervals.
+func CV(intervals []float64) float64 {
+	if len(intervals) < 2 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, v := range intervals {
+		sum += v
+	}
+	mean := sum / float64(len(intervals))
+	var sum2 float64
+	for _, v := range intervals {
+		sum2 += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum2/float64(len(intervals)-1)) / mean
+}
+
+// isiGroup is a labeled set of intervals of one or more spike lines. The
+// pairs hold the consecutive interval pairs of the return map.
+type isiGroup struct {
+	label     string
+	intervals []float64
+	pairs     plotter.XYs
+}
+
+// groups returns the interval groups, one per selected line or one for all
+// selected lines when pooled.
+func (h ISI) groups() []isiGroup {
+	lines := h.Lines
+	if h.Select != nil {
+		lines = make([]SpikeLine, 0, len(h.Select))
+		for _, i := range h.Select {
+			lines = append(lines, h.Lines[i])
+		}
+	}
+	var groups []isiGroup
+	if h.Pooled {
+		groups = append(groups, isiGroup{label: "pooled"})
+	}
+	for i := range lines {
+		intervals := Intervals(lines[i].Spikes)
+		var pairs plotter.XYs
+		for j := 1; j < len(intervals); j++ {
+			pairs = append(pairs, plotter.XY{X: intervals[j-1], Y: intervals[j]})
+		}
+		if h.Pooled {
+			groups[0].intervals = append(groups[0].intervals, intervals...)
+			groups[0].pairs = append(groups[0].pairs, pairs...)
+			continue
+		}
+		groups = append(groups, isiGroup{
+			label:     lines[i].Label,
+			intervals: intervals,
+			pairs:     pairs,
+		})
+	}
+	return groups
+}
+
+// binEdges returns the n+1 bin edges covering the intervals. The edges are
+// logarithmically spaced when LogBins is true, and start at 0 otherwise.
+func (h ISI) binEdges(groups []isiGroup) []float64 {
+	n := h.Bins
+	if n <= 0 {
+		n = 50
+	}
+	lo, hi := math.Inf(1), h.MaxISI
+	for _, g := range groups {
+		for _, v := range g.intervals {
+			if v > 0 && v < lo {
+				lo = v
+			}
+			if h.MaxISI == 0 && v > hi {
+				hi = v
+			}
+		}
+	}
+	if math.IsInf(lo, 1) || !(hi > lo) {
+		return nil
+	}
+	edges := make([]float64, n+1)
+	for i := range edges {
+		if h.LogBins {
+			edges[i] = lo * math.Pow(hi/lo, float64(i)/float64(n))
+		} else {
+			edges[i] = hi * float64(i) / float64(n)
+		}
+	}
+	return edges
+}
+
+// histogram returns the interval counts in the bins defined by the edges.
+// Intervals outside of the edges are ignored.
+func histogram(intervals, edges []float64) []plotter.HistogramBin {
+	bins := make([]plotter.HistogramBin, len(edges)-1)
+	for i := range bins {
+		bins[i].Min, bins[i].Max = edges[i], edges[i+1]
+	}
+	for _, v := range intervals {
+		if v < edges[0] || v > edges[len(edges)-1] {
+			continue
+		}
+		// find the bin with a binary search as log bins are not uniform
+		lo, hi := 0, len(bins)-1
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if v < bins[mid].Max {
+				hi = mid
+			} else {
+				lo = mid + 1
+			}
+		}
+		bins[lo].Weight++
+	}
+	return bins
+}
+
+// newISIPlot returns the ISI histogram plot of the interval groups. Returns
+// an error with LogBins when there are no positive intervals.
+func newISIPlot(h ISI, groups []isiGroup) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = h.Title
+	p.X.Label.Text = fmt.Sprintf("Inter-spike interval (%s)", h.Unit.orSeconds().Name)
+	p.Y.Label.Text = "Count"
+	edges := h.binEdges(groups)
+	if edges == nil {
+		if h.LogBins {
+			return nil, errors.New("no positive intervals for log bins")
+		}
+		return p, nil
+	}
+	if h.LogBins {
+		p.X.Scale = plot.LogScale{}
+		p.X.Tick.Marker = plot.LogTicks{Prec: -1}
+	}
+	colors := h.Colors
+	if colors == nil {
+		colors = DarkColors
+	}
+	for i, g := range groups {
+		bins := histogram(g.intervals, edges)
+		label := fmt.Sprintf("%s (CV=%.2f)", g.label, CV(g.intervals))
+		if h.Pooled {
+			hist := &plotter.Histogram{
+				Bins:      bins,
+				Width:     edges[1] - edges[0],
+				FillColor: color.RGBA{160, 160, 160, 255},
+				LineStyle: draw.LineStyle{
+					Color: color.RGBA{0, 0, 0, 255},
+					Width: vg.Points(1),
+				},
+			}
+			p.Add(hist)
+			p.Legend.Add(label, hist)
+			continue
+		}
+		// draw the histogram outline of each line as steps
+		steps := make(plotter.XYs, 0, len(bins)+1)
+		for _, b := range bins {
+			steps = append(steps, plotter.XY{X: b.Min, Y: b.Weight})
+		}
+		steps = append(steps, plotter.XY{X: edges[len(edges)-1], Y: bins[len(bins)-1].Weight})
+		l := &plotter.Line{
+			XYs:       steps,
+			StepStyle: plotter.PostStep,
+			LineStyle: draw.LineStyle{
+				Color: colors.Id(i),
+				Width: vg.Points(1),
+			},
+		}
+		p.Add(l)
+		p.Legend.Add(label, l)
+	}
+	p.Y.Min = 0
+	p.Legend.Top = true
+	return p, nil
+}
+
+// newReturnMapPlot returns the ISI(n+1) vs ISI(n) return map plot of the
+// interval groups. Returns an error with LogBins when there are no positive
+// interval pairs.
+func newReturnMapPlot(h ISI, groups []isiGroup) (*plot.Plot, error) {
+	p := plot.New()
+	p.Title.Text = h.Title
+	unit := h.Unit.orSeconds().Name
+	p.X.Label.Text = fmt.Sprintf("ISI(n) (%s)", unit)
+	p.Y.Label.Text = fmt.Sprintf("ISI(n+1) (%s)", unit)
+	colors := h.Colors
+	if colors == nil {
+		colors = DarkColors
+	}
+	for i, g := range groups {
+		pairs := g.pairs
+		if h.LogBins {
+			// log axes can't show null intervals
+			pairs = make(plotter.XYs, 0, len(g.pairs))
+			for _, xy := range g.pairs {
+				if xy.X > 0 && xy.Y > 0 {
+					pairs = append(pairs, xy)
+				}
+			}
+		}
+		if len(pairs) == 0 {
+			continue
+		}
+		s := &plotter.Scatter{
+			XYs: pairs,
+			GlyphStyle: draw.GlyphStyle{
+				Shape:  Glyphs.Id(0),
+				Color:  colors.Id(i),
+				Radius: vg.Points(1.5),
+			},
+		}
+		p.Add(s)
+		p.Legend.Add(g.label, s)
+	}
+	if h.LogBins {
+		if math.IsInf(p.X.Min, 1) || !(p.X.Min > 0 && p.Y.Min > 0) {
+			return nil, errors.New("no positive interval pairs for log axes")
+		}
+		p.X.Scale, p.Y.Scale = plot.LogScale{}, plot.LogScale{}
+		p.X.Tick.Marker, p.Y.Tick.Marker = plot.LogTicks{Prec: -1}, plot.LogTicks{Prec: -1}
+	}
+	p.Legend.Top = true
+	return p, nil
+}
+
+// MakeISIPlot generates the inter-spike interval histogram plot, with the
+// return map plot beside it when ReturnMap is true.
+func MakeISIPlot(h ISI, fileNames ...string) error {
+	groups := h.groups()
+	p, err := newISIPlot(h, groups)
+	if err != nil {
+		return fmt.Errorf("isi plot: %w", err)
+	}
+	xDim, yDim := plotDims(h.XDim, h.YDim)
+	if !h.ReturnMap {
+		for _, fileName := range fileNames {
+			err := p.Save(xDim, yDim, fileName)
+			if err != nil {
+				return fmt.Errorf("isi plot: %w", err)
+			}
+		}
+		return nil
+	}
+	r, err := newReturnMapPlot(h, groups)
+	if err != nil {
+		return fmt.Errorf("isi plot: %w", err)
+	}
+	r.Title.Text = ""
+	p.Title.Text = ""
+	plots := [][]*plot.Plot{{p, r}}
+	for _, fileName := range fileNames {
+		err := saveDrawing(xDim, yDim, fileName, func(dc draw.Canvas) {
+			if h.Title != "" {
+				dc = drawTitle(dc, h.Title)
+			}
+			tiles := draw.Tiles{Rows: 1, Cols: 2, PadX: vg.Centimeter}
+			canvases := plot.Align(plots, tiles, dc)
+			p.Draw(canvases[0][0])
+			r.Draw(canvases[0][1])
+		})
+		if err != nil {
+			return fmt.Errorf("isi plot: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/vg"
+)
+
+func TestIntervals(t *testing.T) {
+	intervals := Intervals([]float64{1, 2, 4, 7})
+	if len(intervals) != 3 || intervals[0] != 1 || intervals[1] != 2 || intervals[2] != 3 {
+		t.Fatalf("got intervals %v, expected [1 2 3]", intervals)
+	}
+	if cv := CV(intervals); math.Abs(cv-0.5) > 1e-12 {
+		t.Fatalf("got CV %v, expected 0.5", cv)
+	}
+	if cv := CV(nil); !math.IsNaN(cv) {
+		t.Fatalf("got CV %v, expected NaN", cv)
+	}
+}
+
+func TestISIPlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var lines []SpikeLine
+	for i := 0; i < 3; i++ {
+		lines = append(lines, SpikeLine{
+			Label:  fmt.Sprintf("line %d", i),
+			Spikes: GeneratePoissonDistributedSpikes(20, 10, 0.02),
+		})
+	}
+	h := ISI{
+		Title:     "ISI distribution",
+		Lines:     lines,
+		MaxISI:    0.5,
+		ReturnMap: true,
+		YDim:      9 * vg.Centimeter,
+	}
+	err := MakeISIPlot(h, "tests/isiPlot.png", "tests/isiPlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	h.Pooled = true
+	h.LogBins = true
+	h.MaxISI = 0
+	h.ReturnMap = false
+	err = MakeISIPlot(h, "tests/isiPooledPlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	h.Unit = Milliseconds
+	p, err := newISIPlot(h, h.groups())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.X.Label.Text != "Inter-spike interval (ms)" {
+		t.Errorf("got X label %q, expected the interval unit ms", p.X.Label.Text)
+	}
+}
+
+func TestISISingleSpike(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	h := ISI{Lines: []SpikeLine{{Spikes: []float64{1}}}}
+	if err := MakeISIPlot(h, "tests/isiSingleSpikePlot.png"); err != nil {
+		t.Fatal(err)
+	}
+	h.LogBins = true
+	if err := MakeISIPlot(h, "tests/isiSingleSpikePlot.png"); err == nil {
+		t.Error("expected an error for log bins without intervals")
+	}
+	h.Lines = []SpikeLine{{Spikes: []float64{1, 1.5}}, {Spikes: []float64{1, 1.2}}}
+	h.Pooled = true
+	h.ReturnMap = true
+	if err := MakeISIPlot(h, "tests/isiSingleSpikePlot.png"); err == nil {
+		t.Error("expected an error for a log return map without interval pairs")
+	}
+}
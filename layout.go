@@ -0,0 +1,174 @@
+package plots
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// plotDims returns the plot dimensions with default values when 0.
+func plotDims(xDim, yDim vg.Length) (vg.Length, vg.Length) {
+	if xDim == 0 {
+		xDim = 15 * vg.Centimeter
+	}
+	if yDim == 0 {
+		yDim = 15 * vg.Centimeter
+	}
+	return xDim, yDim
+}
+
+// panel is a plot drawn in a canvas with a data area, like *plot.Plot.
+type panel interface {
+	Draw(c draw.Canvas)
+	DataCanvas(c draw.Canvas) draw.Canvas
+}
+
+// stackPlots returns the canvases where to draw the plots stacked from top to
+// bottom in dc. The height of each canvas is proportional to its weight and
+// the data areas of the plots are horizontally aligned.
+func stackPlots(plots []panel, weights []float64, dc draw.Canvas) []draw.Canvas {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	canvases := make([]draw.Canvas, len(plots))
+	height := dc.Max.Y - dc.Min.Y
+	top := dc.Max.Y
+	for i := range plots {
+		c := dc
+		c.Max.Y = top
+		c.Min.Y = top - vg.Length(weights[i]/total)*height
+		top = c.Min.Y
+		canvases[i] = c
+	}
+	alignX(plots, canvases)
+	return canvases
+}
+
+// alignX crops the canvases of the plots so that the left and right edges of
+// their data areas are aligned.
+func alignX(plots []panel, canvases []draw.Canvas) {
+	var left, right vg.Length
+	for i, p := range plots {
+		c := canvases[i]
+		dataC := p.DataCanvas(c)
+		left = max(left, dataC.Min.X-c.Min.X)
+		right = max(right, c.Max.X-dataC.Max.X)
+	}
+	for i, p := range plots {
+		c := canvases[i]
+		dataC := p.DataCanvas(c)
+		canvases[i] = draw.Crop(c, left-(dataC.Min.X-c.Min.X), c.Max.X-dataC.Max.X-right, 0, 0)
+	}
+}
+
+// alignY crops the canvases of the plots so that the bottom and top edges of
+// their data areas are aligned.
+func alignY(plots []panel, canvases []draw.Canvas) {
+	var bottom, top vg.Length
+	for i, p := range plots {
+		c := canvases[i]
+		dataC := p.DataCanvas(c)
+		bottom = max(bottom, dataC.Min.Y-c.Min.Y)
+		top = max(top, c.Max.Y-dataC.Max.Y)
+	}
+	for i, p := range plots {
+		c := canvases[i]
+		dataC := p.DataCanvas(c)
+		canvases[i] = draw.Crop(c, 0, 0, bottom-(dataC.Min.Y-c.Min.Y), c.Max.Y-dataC.Max.Y-top)
+	}
+}
+
+// drawWithColorBar draws the plot p with the color bar plot on its right in
+// dc. The color bar data area is vertically aligned with the one of p.
+func drawWithColorBar(p, bar *plot.Plot, dc draw.Canvas) {
+	barWidth := 2.5 * vg.Centimeter
+	c := draw.Crop(dc, 0, -barWidth, 0, 0)
+	p.Draw(c)
+	dataC := p.DataCanvas(c)
+	barC := draw.Crop(dc, dc.Max.X-dc.Min.X-barWidth, 0, 0, 0)
+	barC.Min.Y, barC.Max.Y = dataC.Min.Y, dataC.Max.Y
+	barDataC := bar.DataCanvas(barC)
+	barC = draw.Crop(barC, 0, 0, barC.Min.Y-barDataC.Min.Y, barC.Max.Y-barDataC.Max.Y)
+	bar.Draw(barC)
+}
+
+// drawTitle draws the title at the top of dc with a white background and
+// returns the canvas area below the title.
+func drawTitle(dc draw.Canvas, title string) draw.Canvas {
+	p := plot.New()
+	dc.SetColor(p.BackgroundColor)
+	dc.Fill(dc.Rectangle.Path())
+	sty := p.Title.TextStyle
+	dc.FillText(sty, vg.Point{X: dc.Center().X, Y: dc.Max.Y + sty.FontExtents().Descent}, title)
+	dc.Max.Y -= sty.Rectangle(title).Size().Y + p.Title.Padding
+	return dc
+}
+
+// saveDrawing saves the drawing made by drawFn in a canvas of the given size.
+// The file format is determined by the file name extension.
+func saveDrawing(xDim, yDim vg.Length, fileName string, drawFn func(dc draw.Canvas)) (err error) {
+	format := strings.ToLower(filepath.Ext(fileName))
+	if len(format) != 0 {
+		format = format[1:]
+	}
+	c, err := newDrawing(xDim, yDim, format, drawFn)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := f.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	_, err = c.WriteTo(f)
+	return err
+}
+
+// writeDrawing writes the drawing made by drawFn in a canvas of the given
+// size to w in the given format.
+func writeDrawing(w io.Writer, xDim, yDim vg.Length, format string, drawFn func(dc draw.Canvas)) error {
+	c, err := newDrawing(xDim, yDim, format, drawFn)
+	if err != nil {
+		return err
+	}
+	_, err = c.WriteTo(w)
+	return err
+}
+
+// newDrawing returns the canvas of the given size and format with the
+// drawing made by drawFn.
+func newDrawing(xDim, yDim vg.Length, format string, drawFn func(dc draw.Canvas)) (vg.CanvasWriterTo, error) {
+	c, err := draw.NewFormattedCanvas(xDim, yDim, format)
+	if err != nil {
+		return nil, err
+	}
+	drawFn(draw.New(c))
+	return c, nil
+}
+
+// saveStacked saves the plots stacked from top to bottom with heights
+// proportional to their weight.
+func saveStacked(plots []panel, weights []float64, xDim, yDim vg.Length, fileNames ...string) error {
+	for _, fileName := range fileNames {
+		err := saveDrawing(xDim, yDim, fileName, func(dc draw.Canvas) {
+			for i, c := range stackPlots(plots, weights, dc) {
+				plots[i].Draw(c)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
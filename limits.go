@@ -0,0 +1,144 @@
+package plots
+
+import (
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// setRanges sets the axis ranges of the line plot from the limits, or from
+// the automatic ranges made symmetric and padded as requested.
+func (l Lines) setRanges(p *plot.Plot) {
+	setRange(&p.X, l.XLimit, l.XScale, l.XSymmetric, l.Padding)
+	setRange(&p.Y, l.YLimit, l.YScale, l.YSymmetric, l.Padding)
+}
+
+// setRange sets the range of the axis to the limit if not nil. Otherwise the
+// automatic range is made symmetric around 0 when requested, except with a
+// log scale, and is widened on both sides by the padding fraction of its
+// width. The padding of a log scale is a fraction of the logarithmic width,
+// and the empty range of a log scale without data is [1,10].
+func setRange(a *plot.Axis, limit *Limit, scale Scale, symmetric bool, padding float64) {
+	if limit != nil {
+		a.Min, a.Max = limit.Min, limit.Max
+		return
+	}
+	if scale.isLog() {
+		if !(a.Min > 0) || math.IsInf(a.Max, 0) {
+			a.Min, a.Max = 1, 10
+		}
+		if padding > 0 && a.Min > 0 {
+			d := padding * math.Log(a.Max/a.Min)
+			a.Min, a.Max = a.Min*math.Exp(-d), a.Max*math.Exp(d)
+		}
+		return
+	}
+	if symmetric {
+		m := max(math.Abs(a.Min), math.Abs(a.Max))
+		a.Min, a.Max = -m, m
+	}
+	if padding > 0 {
+		d := padding * (a.Max - a.Min)
+		a.Min, a.Max = a.Min-d, a.Max+d
+	}
+}
+
+// equalAspect widens the X or Y axis range of the plot around its center so
+// that the X and Y units have the same length in the data area of the plot
+// drawn in c. The data area depends on the tick labels, so that the ranges
+// are adjusted a few times.
+func equalAspect(p *plot.Plot, c draw.Canvas) {
+	xMin, xMax, yMin, yMax := p.X.Min, p.X.Max, p.Y.Min, p.Y.Max
+	for i := 0; i < 3; i++ {
+		dc := p.DataCanvas(c)
+		w, h := float64(dc.Size().X), float64(dc.Size().Y)
+		if w <= 0 || h <= 0 {
+			return
+		}
+		p.X.Min, p.X.Max, p.Y.Min, p.Y.Max = xMin, xMax, yMin, yMax
+		xRange, yRange := xMax-xMin, yMax-yMin
+		if xRange/w > yRange/h {
+			yCenter, yRange := (yMin+yMax)/2, xRange*h/w
+			p.Y.Min, p.Y.Max = yCenter-yRange/2, yCenter+yRange/2
+		} else {
+			xCenter, xRange := (xMin+xMax)/2, yRange*w/h
+			p.X.Min, p.X.Max = xCenter-xRange/2, xCenter+xRange/2
+		}
+	}
+}
+
+// clipped returns the plotter drawing only the glyphs and error bars inside
+// the data area. The lines and polygons are already clipped by gonum.
+func clipped(pl plot.Plotter) plot.Plotter {
+	switch pl := pl.(type) {
+	case *plotter.Scatter:
+		return clippedScatter{pl}
+	case *plotter.YErrorBars:
+		return clippedYErrorBars{pl}
+	case *plotter.XErrorBars:
+		return clippedXErrorBars{pl}
+	}
+	return pl
+}
+
+// clippedScatter is a scatter plotter drawing only the glyphs inside the
+// data area.
+type clippedScatter struct {
+	*plotter.Scatter
+}
+
+// Plot draws the glyphs inside the data area.
+func (s clippedScatter) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	r := s.GlyphStyle.Radius
+	for _, xy := range s.XYs {
+		pt := vg.Point{X: trX(xy.X), Y: trY(xy.Y)}
+		if pt.X-r < c.Min.X || pt.X+r > c.Max.X || pt.Y-r < c.Min.Y || pt.Y+r > c.Max.Y {
+			continue
+		}
+		c.DrawGlyph(s.GlyphStyle, pt)
+	}
+}
+
+// clippedYErrorBars are Y error bars drawn only where their caps are inside
+// the data area horizontally.
+type clippedYErrorBars struct {
+	*plotter.YErrorBars
+}
+
+// Plot draws the error bars inside the data area.
+func (e clippedYErrorBars) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, _ := p.Transforms(&c)
+	bars := *e.YErrorBars
+	bars.XYs, bars.YErrors = nil, nil
+	for i, xy := range e.XYs {
+		if x := trX(xy.X); x-e.CapWidth/2 >= c.Min.X && x+e.CapWidth/2 <= c.Max.X {
+			bars.XYs = append(bars.XYs, xy)
+			bars.YErrors = append(bars.YErrors, e.YErrors[i])
+		}
+	}
+	bars.Plot(c, p)
+}
+
+// clippedXErrorBars are X error bars drawn only where their caps are inside
+// the data area vertically.
+type clippedXErrorBars struct {
+	*plotter.XErrorBars
+}
+
+// Plot draws the error bars inside the data area.
+func (e clippedXErrorBars) Plot(c draw.Canvas, p *plot.Plot) {
+	_, trY := p.Transforms(&c)
+	bars := *e.XErrorBars
+	bars.XYs, bars.XErrors = nil, nil
+	for i, xy := range e.XYs {
+		if y := trY(xy.Y); y-e.CapWidth/2 >= c.Min.Y && y+e.CapWidth/2 <= c.Max.Y {
+			bars.XYs = append(bars.XYs, xy)
+			bars.XErrors = append(bars.XErrors, e.XErrors[i])
+		}
+	}
+	bars.Plot(c, p)
+}
@@ -0,0 +1,84 @@
+package plots
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+	"gonum.org/v1/plot/vg/recorder"
+)
+
+func TestLimitLinePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var circle, points plotter.XYs
+	for a := 0.; a < 2*math.Pi; a += math.Pi / 32 {
+		circle = append(circle, plotter.XY{X: 2 + math.Cos(a), Y: 1 + math.Sin(a)})
+	}
+	for x := 0.; x <= 4; x += 0.25 {
+		points = append(points, plotter.XY{X: x, Y: x - 1})
+	}
+	lines := Lines{
+		Title:       "equal aspect ratio",
+		Lines:       []Line{{Label: "circle", Points: circle, Color: DarkColors.Id(1)}},
+		Padding:     0.1,
+		EqualAspect: true,
+		XDim:        15 * vg.Centimeter,
+		YDim:        10 * vg.Centimeter,
+	}
+	if err := MakeLinePlot(lines, "tests/equalAspectLinePlot.png"); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewLinePlot(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xRange, yRange := [2]float64{p.X.Min, p.X.Max}, [2]float64{p.Y.Min, p.Y.Max}
+	for _, dims := range [][2]vg.Length{{15 * vg.Centimeter, 10 * vg.Centimeter}, {8 * vg.Centimeter, 20 * vg.Centimeter}} {
+		c := draw.Canvas{Canvas: &recorder.Canvas{}, Rectangle: vg.Rectangle{Max: vg.Point{X: dims[0], Y: dims[1]}}}
+		pc := p.drawn(c)
+		size := p.DataCanvas(c).Size()
+		xUnit := float64(size.X) / (pc.X.Max - pc.X.Min)
+		yUnit := float64(size.Y) / (pc.Y.Max - pc.Y.Min)
+		if math.Abs(xUnit-yUnit) > 0.01*yUnit {
+			t.Errorf("got X unit %g and Y unit %g in a %vx%v plot, want equal", xUnit, yUnit, dims[0], dims[1])
+		}
+	}
+	if xRange != [2]float64{p.X.Min, p.X.Max} || yRange != [2]float64{p.Y.Min, p.Y.Max} {
+		t.Errorf("got ranges [%g,%g] and [%g,%g] changed by drawing", p.X.Min, p.X.Max, p.Y.Min, p.Y.Max)
+	}
+
+	lines = Lines{
+		Title: "limits and clipping",
+		Lines: []Line{
+			{
+				Label:   "points",
+				Points:  points,
+				Color:   DarkColors.Id(2),
+				Glyph:   Glyphs.Id(0),
+				YErrors: make([]float64, len(points)),
+			},
+		},
+		XLimit:     &Limit{Min: 1, Max: 3},
+		YSymmetric: true,
+		Clip:       true,
+	}
+	for i := range lines.Lines[0].YErrors {
+		lines.Lines[0].YErrors[i] = 0.2
+	}
+	if err := MakeLinePlot(lines, "tests/limitLinePlot.png"); err != nil {
+		t.Fatal(err)
+	}
+	p, err = NewLinePlot(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.X.Min != 1 || p.X.Max != 3 {
+		t.Errorf("got X range [%g,%g], want [1,3]", p.X.Min, p.X.Max)
+	}
+	if p.Y.Min != -p.Y.Max || p.Y.Max < 3.2 {
+		t.Errorf("got Y range [%g,%g], want symmetric", p.Y.Min, p.Y.Max)
+	}
+}
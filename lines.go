@@ -1,8 +1,10 @@
 package plots
 
 import (
+	"errors"
 	"fmt"
 	"image/color"
+	"math"
 
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
@@ -48,48 +50,185 @@ type Line struct {
 	Glyph       draw.GlyphDrawer // Glyph to draw.
 	GlyphColor  color.Color      // Glyph color.
 	GlyphRadius vg.Length        // Glyph size.
+	XErrors     []float64        // X uncertainty of each point, none if nil.
+	YErrors     []float64        // Y uncertainty of each point, none if nil.
+	Band        bool             // Draw the Y uncertainty as a band instead of error bars.
+	RightAxis   bool             // Line uses the right Y axis.
+	Mode        LineMode         // Way the points are connected (default = StraightMode).
+	Baseline    float64          // Baseline of StemMode and AreaMode (default = 0).
+	FillTo      plotter.XYer     // Other boundary of the filled area of FillBetweenMode.
+	FillColor   color.Color      // Color of the filled area (default = Color).
+	FillAlpha   float64          // Opacity of the filled area (default = 0.3).
 }
 
 // Lines is a set of lines to be drawn.
 type Lines struct {
-	Title  string    // Line plot title.
-	XLabel string    // X axis label, none if empty.
-	YLabel string    // Y axis label, none if empty.
-	Lines  []Line    // Lines to draw in plot.
-	XDim   vg.Length // X dimension of saved plot, use default if 0.
-	YDim   vg.Length // Y dimension of saved plot, use default if 0.
+	Title       string    // Line plot title.
+	XLabel      string    // X axis label, none if empty.
+	YLabel      string    // Y axis label, none if empty.
+	Lines       []Line    // Lines to draw in plot.
+	Epochs      Epochs    // X intervals drawn as bands behind the lines.
+	XScale      Scale     // X axis scale (default = linear).
+	YScale      Scale     // Y axis scale (default = linear).
+	XLimit      *Limit    // X axis range, automatic if nil.
+	YLimit      *Limit    // Y axis range, automatic if nil.
+	XSymmetric  bool      // Automatic X range is symmetric around 0, except with a log scale.
+	YSymmetric  bool      // Automatic Y range is symmetric around 0, except with a log scale.
+	Padding     float64   // Fraction of the automatic ranges added on both sides (default = 0).
+	EqualAspect bool      // X and Y units have the same length with linear scales.
+	Clip        bool      // Draw only the glyphs and error bars inside the data area.
+	RightLabel  string    // Right Y axis label, none if empty.
+	RightScale  Scale     // Right Y axis scale (default = linear).
+	RightLimit  *Limit    // Right Y axis range, automatic if nil.
+	XDim        vg.Length // X dimension of saved plot, use default if 0.
+	YDim        vg.Length // Y dimension of saved plot, use default if 0.
 }
 
 // MakeLinePlot generates the line plot.
 func MakeLinePlot(lines Lines, fileNames ...string) error {
+	p, err := NewLinePlot(lines)
+	if err != nil {
+		return err
+	}
+	xDim, yDim := plotDims(lines.XDim, lines.YDim)
+	for _, fileName := range fileNames {
+		err := p.Save(xDim, yDim, fileName)
+		if err != nil {
+			return fmt.Errorf("line plot: %w", err)
+		}
+	}
+	return nil
+}
+
+// LinePlot is the plot of lines. With EqualAspect, the axis ranges are
+// widened when drawing to the data area of the canvas. The embedded plot may
+// be customized before drawing or saving it.
+type LinePlot struct {
+	*plot.Plot
+	equalAspect bool // X and Y units have the same length.
+}
+
+// NewLinePlot returns the plot of the lines.
+func NewLinePlot(lines Lines) (*LinePlot, error) {
+	p, _, err := newLinePlot(lines)
+	if err != nil {
+		return nil, err
+	}
+	equal := lines.EqualAspect && lines.XScale.Type == LinearScale && lines.YScale.Type == LinearScale
+	return &LinePlot{Plot: p, equalAspect: equal}, nil
+}
+
+// drawn returns the plot drawn in c, which is a copy of the embedded plot
+// with equal aspect ranges if requested.
+func (p *LinePlot) drawn(c draw.Canvas) *plot.Plot {
+	if !p.equalAspect {
+		return p.Plot
+	}
+	pc := *p.Plot
+	equalAspect(&pc, c)
+	return &pc
+}
+
+// DataCanvas returns the data area of the plot drawn in c.
+func (p *LinePlot) DataCanvas(c draw.Canvas) draw.Canvas {
+	return p.drawn(c).DataCanvas(c)
+}
+
+// Draw draws the plot in c.
+func (p *LinePlot) Draw(c draw.Canvas) {
+	p.drawn(c).Draw(c)
+}
+
+// Save saves the plot of the given size in the file. The file format is
+// determined by the file name extension.
+func (p *LinePlot) Save(xDim, yDim vg.Length, fileName string) error {
+	return saveDrawing(xDim, yDim, fileName, p.Draw)
+}
+
+// newLinePlot returns the plot of the lines and its right axis, nil if no
+// line uses it. When all lines use the right axis, the left axis has the
+// range of the right axis unless YLimit is set.
+func newLinePlot(lines Lines) (*plot.Plot, *rightAxis, error) {
 	p := plot.New()
 	p.Title.Text = lines.Title
 	p.X.Label.Text = lines.XLabel
 	p.Y.Label.Text = lines.YLabel
-	for i := range lines.Lines {
-		err := Add(p, lines.Lines[i])
-		if err != nil {
-			return fmt.Errorf("line plot '%s': %w", lines.Lines[i].Label, err)
-		}
+	if len(lines.Epochs) != 0 {
+		p.Add(lines.Epochs)
 	}
-	xDim, yDim := lines.XDim, lines.YDim
-	if xDim == 0 {
-		xDim = 15 * vg.Centimeter
+	lines.XScale.apply(&p.X)
+	lines.YScale.apply(&p.Y)
+	if lines.XLimit != nil && lines.XScale.isLog() && !(lines.XLimit.Min > 0) ||
+		lines.YLimit != nil && lines.YScale.isLog() && !(lines.YLimit.Min > 0) ||
+		lines.RightLimit != nil && lines.RightScale.isLog() && !(lines.RightLimit.Min > 0) {
+		return nil, nil, errors.New("line plot: non-positive limit with a log scale")
 	}
-	if yDim == 0 {
-		yDim = 15 * vg.Centimeter
+	var right *rightAxis
+	hasLeft := false
+	for _, line := range lines.Lines {
+		if !line.RightAxis {
+			hasLeft = true
+		} else if right == nil {
+			right = newRightAxis(lines.RightScale, lines.RightLabel)
+		}
 	}
-	for _, fileName := range fileNames {
-		err := p.Save(xDim, yDim, fileName)
+	for _, line := range lines.Lines {
+		yScale, label := lines.YScale, line.Label
+		if right != nil {
+			label += " (left)"
+			if line.RightAxis {
+				yScale, label = lines.RightScale, line.Label+" (right)"
+			}
+		}
+		err := checkScales(line, lines.XScale, yScale)
+		var plotters []plot.Plotter
+		var thumbs []plot.Thumbnailer
+		if err == nil {
+			plotters, thumbs, err = linePlotters(line, lines.Clip)
+		}
 		if err != nil {
-			return fmt.Errorf("line plot: %w", err)
+			return nil, nil, fmt.Errorf("line plot '%s': %w", line.Label, err)
+		}
+		if line.RightAxis {
+			right.Add(plotters...)
+		} else {
+			p.Add(plotters...)
+		}
+		if line.Label != "" && len(thumbs) != 0 {
+			p.Legend.Add(label, thumbs...)
 		}
 	}
-	return nil
+	if right != nil {
+		right.setRange(lines.RightLimit, lines.RightScale, lines.Padding)
+		p.Add(right)
+		p.Legend.XOffs = -right.width()
+	}
+	lines.setRanges(p)
+	if right != nil && !hasLeft && lines.YLimit == nil {
+		if y := right.right.Y; !lines.YScale.isLog() || y.Min > 0 {
+			p.Y.Min, p.Y.Max = y.Min, y.Max
+		}
+	}
+	return p, right, nil
 }
 
 // Add adds the points to the plot using the given style options.
 func Add(plt *plot.Plot, line Line) error {
+	plotters, thumbs, err := linePlotters(line, false)
+	if err != nil {
+		return err
+	}
+	plt.Add(plotters...)
+	if line.Label != "" && len(thumbs) != 0 {
+		plt.Legend.Add(line.Label, thumbs...)
+	}
+	return nil
+}
+
+// linePlotters returns the plotters of the line with default style values,
+// and its legend thumbnails. Only the glyphs and error bars inside the data
+// area are drawn if clip is true.
+func linePlotters(line Line, clip bool) ([]plot.Plotter, []plot.Thumbnailer, error) {
 	var hasProperty bool
 	if line.Color != nil || line.Width != 0 ||
 		line.Dashes != nil || line.DashOffs != 0 {
@@ -125,26 +264,69 @@ func Add(plt *plot.Plot, line Line) error {
 		line.Color = rgb(20, 20, 20)
 		line.Width = vg.Points(1)
 	}
+	if line.Mode == StemMode {
+		// the stems are drawn with the glyph color when only glyph
+		// properties are set
+		if line.Color == nil {
+			line.Color = line.GlyphColor
+		}
+		if line.Width == 0 {
+			line.Width = vg.Points(1)
+		}
+		if line.GlyphRadius == 0 {
+			if line.Glyph == nil {
+				line.Glyph = Glyphs.Id(0)
+			}
+			if line.GlyphColor == nil {
+				line.GlyphColor = line.Color
+			}
+			line.GlyphRadius = line.Width + vg.Points(1)
+		}
+	}
 
-	var l *plotter.Line
-	var s *plotter.Scatter
 	xys, err := plotter.CopyXYs(line.Points)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	if line.Width != 0 {
-		l = &plotter.Line{
-			XYs: xys,
-			LineStyle: draw.LineStyle{
-				Color:    line.Color,
-				Width:    line.Width,
-				Dashes:   line.Dashes,
-				DashOffs: line.DashOffs,
-			},
+	var plotters []plot.Plotter
+	var thumbs []plot.Thumbnailer
+	if line.YErrors != nil || line.XErrors != nil {
+		ps, ts, err := errorPlotters(line, xys)
+		if err != nil {
+			return nil, nil, err
+		}
+		plotters = append(plotters, ps...)
+		thumbs = append(thumbs, ts...)
+	}
+	if line.Mode == AreaMode || line.Mode == FillBetweenMode {
+		poly, err := fillPlotter(line, xys)
+		if err != nil {
+			return nil, nil, err
+		}
+		plotters = append(plotters, poly)
+		thumbs = append(thumbs, poly)
+	}
+	lineStyle := draw.LineStyle{
+		Color:    line.Color,
+		Width:    line.Width,
+		Dashes:   line.Dashes,
+		DashOffs: line.DashOffs,
+	}
+	if line.Mode == StemMode {
+		s := &stems{XYs: xys, LineStyle: lineStyle, baseline: line.Baseline}
+		plotters = append(plotters, s)
+		thumbs = append(thumbs, s)
+	} else if line.Width != 0 {
+		l := &plotter.Line{
+			XYs:       xys,
+			LineStyle: lineStyle,
+			StepStyle: line.Mode.stepKind(),
 		}
+		plotters = append(plotters, l)
+		thumbs = append(thumbs, l)
 	}
 	if line.GlyphRadius != 0 {
-		s = &plotter.Scatter{
+		s := &plotter.Scatter{
 			XYs: xys,
 			GlyphStyle: draw.GlyphStyle{
 				Shape:  line.Glyph,
@@ -152,24 +334,76 @@ func Add(plt *plot.Plot, line Line) error {
 				Radius: line.GlyphRadius,
 			},
 		}
+		plotters = append(plotters, s)
+		thumbs = append(thumbs, s)
+	}
+	if clip {
+		for i := range plotters {
+			plotters[i] = clipped(plotters[i])
+		}
+	}
+	return plotters, thumbs, nil
+}
+
+// errorPlotters returns the plotters of the X and Y uncertainties of the line
+// points, drawn as error bars with caps, or as a semi-transparent band for
+// the Y uncertainty when line.Band is true. The band is also returned as a
+// legend thumbnail.
+func errorPlotters(line Line, xys plotter.XYs) ([]plot.Plotter, []plot.Thumbnailer, error) {
+	if line.XErrors != nil && len(line.XErrors) != len(xys) {
+		return nil, nil, fmt.Errorf("%d X errors for %d points", len(line.XErrors), len(xys))
+	}
+	if line.YErrors != nil && len(line.YErrors) != len(xys) {
+		return nil, nil, fmt.Errorf("%d Y errors for %d points", len(line.YErrors), len(xys))
+	}
+	lineColor := line.Color
+	if lineColor == nil {
+		lineColor = line.GlyphColor
+	}
+	lineStyle := draw.LineStyle{
+		Color: lineColor,
+		Width: max(line.Width, vg.Points(1)),
 	}
-	switch {
-	case l != nil && s != nil:
-		plt.Add(l, s)
-	case l != nil && s == nil:
-		plt.Add(l)
-	case l == nil && s != nil:
-		plt.Add(s)
+	var plotters []plot.Plotter
+	var thumbs []plot.Thumbnailer
+	if line.YErrors != nil && line.Band {
+		band := make(plotter.XYs, 2*len(xys))
+		for i, xy := range xys {
+			e := math.Abs(line.YErrors[i])
+			band[i] = plotter.XY{X: xy.X, Y: xy.Y + e}
+			band[len(band)-1-i] = plotter.XY{X: xy.X, Y: xy.Y - e}
+		}
+		poly, err := plotter.NewPolygon(band)
+		if err != nil {
+			return nil, nil, err
+		}
+		poly.Color = withAlpha(lineColor, 64)
+		poly.LineStyle.Width = 0
+		plotters = append(plotters, poly)
+		thumbs = append(thumbs, poly)
+	} else if line.YErrors != nil {
+		yErrors := make(plotter.YErrors, len(xys))
+		for i, e := range line.YErrors {
+			yErrors[i].Low, yErrors[i].High = e, e
+		}
+		plotters = append(plotters, &plotter.YErrorBars{
+			XYs:       xys,
+			YErrors:   yErrors,
+			LineStyle: lineStyle,
+			CapWidth:  plotter.DefaultCapWidth,
+		})
 	}
-	if line.Label != "" {
-		switch {
-		case l != nil && s != nil:
-			plt.Legend.Add(line.Label, l, s)
-		case l != nil && s == nil:
-			plt.Legend.Add(line.Label, l)
-		case l == nil && s != nil:
-			plt.Legend.Add(line.Label, s)
+	if line.XErrors != nil {
+		xErrors := make(plotter.XErrors, len(xys))
+		for i, e := range line.XErrors {
+			xErrors[i].Low, xErrors[i].High = e, e
 		}
+		plotters = append(plotters, &plotter.XErrorBars{
+			XYs:       xys,
+			XErrors:   xErrors,
+			LineStyle: lineStyle,
+			CapWidth:  plotter.DefaultCapWidth,
+		})
 	}
-	return nil
+	return plotters, thumbs, nil
 }
@@ -116,3 +116,75 @@ func TestLinePlot3(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestEpochLinePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var sinXYs plotter.XYs
+	for x := 0.; x < 10; x += 0.05 {
+		sinXYs = append(sinXYs, plotter.XY{X: x, Y: math.Sin(x)})
+	}
+	lines := Lines{
+		Title: "lines with epochs",
+		Lines: []Line{
+			{
+				Label:  "Sin",
+				Points: sinXYs,
+				Color:  DarkColors.Id(1),
+			},
+		},
+		Epochs: Epochs{
+			{Start: -1, End: 1, Label: "clipped"},
+			{Start: 4, End: 6, Label: "stimulus", Color: DarkColors.Id(2), Alpha: 0.2},
+		},
+	}
+	err := MakeLinePlot(lines, "tests/epochLinePlot.png", "tests/epochLinePlot.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestErrorLinePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var means, errs, sems []float64
+	for i := 0; i < 20; i++ {
+		x := float64(i) / 2
+		means = append(means, math.Sin(x))
+		errs = append(errs, 0.1+0.02*float64(i))
+		sems = append(sems, 0.05)
+	}
+	lines := Lines{
+		Title: "lines with uncertainties",
+		Lines: []Line{
+			{
+				Label:   "error bars",
+				Points:  XYs(means),
+				Color:   DarkColors.Id(1),
+				Glyph:   Glyphs.Id(0),
+				YErrors: errs,
+				XErrors: sems,
+			},
+			{
+				Label:   "band",
+				Points:  XYs(means[5:]),
+				Color:   DarkColors.Id(2),
+				YErrors: errs[5:],
+				Band:    true,
+			},
+		},
+	}
+	err := MakeLinePlot(lines, "tests/errorLinePlot.png", "tests/errorLinePlot.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewLinePlot(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Y.Max < means[3]+errs[3] {
+		t.Errorf("got Y max %g, want at least %g", p.Y.Max, means[3]+errs[3])
+	}
+	lines.Lines[1].YErrors = errs
+	if _, err := NewLinePlot(lines); err == nil {
+		t.Error("expected an error for the mismatched number of Y errors")
+	}
+}
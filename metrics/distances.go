@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"math"
+
+	"github.com/chmike/plots"
+)
+
+// VictorPurpura returns the Victor–Purpura distance between the spike lines.
+// It is the minimal cost of transforming one spike train into the other,
+// where inserting or deleting a spike costs 1 and shifting a spike by dt
+// costs q*|dt|.
+func VictorPurpura(a, b plots.SpikeLine, q float64) float64 {
+	x, y := a.Spikes, b.Spikes
+	prev := make([]float64, len(y)+1)
+	cur := make([]float64, len(y)+1)
+	for j := range prev {
+		prev[j] = float64(j)
+	}
+	for i := range x {
+		cur[0] = float64(i + 1)
+		for j := range y {
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+q*math.Abs(x[i]-y[j]))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(y)]
+}
+
+// VanRossum returns the van Rossum distance between the spike lines with the
+// exponential kernel time constant tau. It is the square root of the
+// integral of the squared difference of the filtered spike trains divided
+// by tau, so that a single unmatched spike is at distance sqrt(1/2).
+func VanRossum(a, b plots.SpikeLine, tau float64) float64 {
+	sum := func(x, y []float64) float64 {
+		var s float64
+		for _, u := range x {
+			for _, v := range y {
+				s += math.Exp(-math.Abs(u-v) / tau)
+			}
+		}
+		return s
+	}
+	d2 := (sum(a.Spikes, a.Spikes) + sum(b.Spikes, b.Spikes) - 2*sum(a.Spikes, b.Spikes)) / 2
+	return math.Sqrt(max(d2, 0))
+}
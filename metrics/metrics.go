@@ -0,0 +1,62 @@
+// Package metrics provides spike train distance, synchrony and variability
+// measures of spike lines.
+//
+// The time-resolved profiles are returned as plotter.XYs values that may be
+// used as the Points of a plots.Line.
+package metrics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chmike/plots"
+)
+
+// CV returns the coefficient of variation of the inter-spike intervals of
+// the spike line, or NaN if it has less than 3 spikes.
+func CV(l plots.SpikeLine) float64 {
+	return plots.CV(plots.Intervals(l.Spikes))
+}
+
+// FanoFactor returns the Fano factor of the spike counts of the lines in
+// [tStart,tEnd), like repeated trials of a neuron. Returns NaN if there are
+// less than 2 lines, no spikes or tStart >= tEnd.
+func FanoFactor(lines []plots.SpikeLine, tStart, tEnd float64) float64 {
+	if len(lines) < 2 || !(tStart < tEnd) {
+		return math.NaN()
+	}
+	counts := make([]float64, len(lines))
+	var mean float64
+	for i := range lines {
+		beg := sort.SearchFloat64s(lines[i].Spikes, tStart)
+		end := sort.SearchFloat64s(lines[i].Spikes, tEnd)
+		counts[i] = float64(end - beg)
+		mean += counts[i]
+	}
+	mean /= float64(len(counts))
+	if mean == 0 {
+		return math.NaN()
+	}
+	var variance float64
+	for _, v := range counts {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(counts) - 1)
+	return variance / mean
+}
+
+// Matrix returns the symmetric matrix of the measure between all pairs of
+// spike lines.
+func Matrix(lines []plots.SpikeLine, measure func(a, b plots.SpikeLine) float64) [][]float64 {
+	m := make([][]float64, len(lines))
+	for i := range m {
+		m[i] = make([]float64, len(lines))
+	}
+	for i := range lines {
+		for j := i; j < len(lines); j++ {
+			m[i][j] = measure(lines[i], lines[j])
+			m[j][i] = m[i][j]
+		}
+	}
+	return m
+}
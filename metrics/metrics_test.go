@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chmike/plots"
+	"gonum.org/v1/plot/plotter"
+)
+
+func line(spikes ...float64) plots.SpikeLine {
+	return plots.SpikeLine{Spikes: spikes}
+}
+
+func TestVictorPurpura(t *testing.T) {
+	for _, test := range []struct {
+		a, b plots.SpikeLine
+		q    float64
+		d    float64
+	}{
+		{line(1), line(), 1, 1},
+		{line(1), line(1.5), 1, 0.5},
+		{line(1), line(1.5), 10, 2},
+		{line(1, 2, 3), line(1, 2, 3), 1, 0},
+	} {
+		if d := VictorPurpura(test.a, test.b, test.q); math.Abs(d-test.d) > 1e-12 {
+			t.Errorf("got distance %v between %v and %v, expected %v", d, test.a.Spikes, test.b.Spikes, test.d)
+		}
+	}
+}
+
+func TestVanRossum(t *testing.T) {
+	if d := VanRossum(line(1), line(), 0.01); math.Abs(d-math.Sqrt(0.5)) > 1e-12 {
+		t.Errorf("got distance %v, expected %v", d, math.Sqrt(0.5))
+	}
+	if d := VanRossum(line(1, 2), line(1, 2), 0.01); d != 0 {
+		t.Errorf("got distance %v, expected 0", d)
+	}
+}
+
+func TestISIDistance(t *testing.T) {
+	a, b := line(0, 1, 2, 3, 4), line(0, 2, 4)
+	if d := ISIDistance(a, b, 0, 4); d != 0.5 {
+		t.Errorf("got distance %v, expected 0.5", d)
+	}
+	if d := ISIDistance(a, a, 0, 4); d != 0 {
+		t.Errorf("got distance %v, expected 0", d)
+	}
+	profile := ISIDistanceProfile(a, b, 0, 4)
+	if len(profile) != 8 || profile[0].X != 0 || profile[7].X != 4 {
+		t.Errorf("got profile %v", profile)
+	}
+}
+
+func TestSpikeDistance(t *testing.T) {
+	a, b := line(1, 3), line(1.5, 3.5)
+	if d := SpikeDistance(a, a, 0, 4); d != 0 {
+		t.Errorf("got distance %v, expected 0", d)
+	}
+	d := SpikeDistance(a, b, 0, 4)
+	if !(d > 0 && d < 1) {
+		t.Errorf("got distance %v, expected in (0,1)", d)
+	}
+	if d2 := SpikeDistance(b, a, 0, 4); math.Abs(d-d2) > 1e-12 {
+		t.Errorf("got distance %v, expected symmetric %v", d2, d)
+	}
+}
+
+func TestSpikeSync(t *testing.T) {
+	if s := SpikeSync(line(1, 2, 3), line(1, 2, 3), 0, 4); s != 1 {
+		t.Errorf("got synchronization %v, expected 1", s)
+	}
+	if s := SpikeSync(line(1), line(3), 0, 4); s != 0 {
+		t.Errorf("got synchronization %v, expected 0", s)
+	}
+	if s := SpikeSync(line(1, 2, 3), line(1.1, 3.5), 0, 4); s != 0.4 {
+		t.Errorf("got synchronization %v, expected 0.4", s)
+	}
+	if s := SpikeSync(line(), line(), 0, 4); s != 1 {
+		t.Errorf("got synchronization %v, expected 1", s)
+	}
+}
+
+func TestReversedRange(t *testing.T) {
+	a, b := line(1, 2, 3), line(1.5, 2.5)
+	for _, profile := range []func(a, b plots.SpikeLine, tStart, tEnd float64) plotter.XYs{
+		ISIDistanceProfile, SpikeDistanceProfile, SpikeSyncProfile,
+	} {
+		if p := profile(a, b, 4, 0); p != nil {
+			t.Errorf("got profile %v, expected nil", p)
+		}
+	}
+	if d := ISIDistance(a, b, 4, 0); d != 0 {
+		t.Errorf("got distance %v, expected 0", d)
+	}
+	if d := SpikeDistance(a, b, 4, 0); d != 0 {
+		t.Errorf("got distance %v, expected 0", d)
+	}
+	if s := SpikeSync(a, b, 4, 0); s != 1 {
+		t.Errorf("got synchronization %v, expected 1", s)
+	}
+	if f := FanoFactor([]plots.SpikeLine{a, b}, 4, 0); !math.IsNaN(f) {
+		t.Errorf("got Fano factor %v, expected NaN", f)
+	}
+}
+
+func TestFanoFactor(t *testing.T) {
+	lines := []plots.SpikeLine{line(0.5), line(0.1, 0.5, 0.9, 2)}
+	if f := FanoFactor(lines, 0, 1); f != 1 {
+		t.Errorf("got Fano factor %v, expected 1", f)
+	}
+	if f := FanoFactor(lines[:1], 0, 1); !math.IsNaN(f) {
+		t.Errorf("got Fano factor %v, expected NaN", f)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	lines := []plots.SpikeLine{line(1), line(1.5), line()}
+	m := Matrix(lines, func(a, b plots.SpikeLine) float64 { return VictorPurpura(a, b, 1) })
+	if m[0][1] != 0.5 || m[1][0] != 0.5 || m[2][0] != 1 || m[1][1] != 0 {
+		t.Errorf("got matrix %v", m)
+	}
+}
@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chmike/plots"
+	"gonum.org/v1/plot/plotter"
+)
+
+// withEdges returns the spikes in [tStart,tEnd] with auxiliary spikes at
+// tStart and tEnd when not already present. Requires that tStart <= tEnd.
+func withEdges(spikes []float64, tStart, tEnd float64) []float64 {
+	beg := sort.SearchFloat64s(spikes, tStart)
+	end := sort.Search(len(spikes), func(i int) bool { return spikes[i] > tEnd })
+	res := make([]float64, 0, end-beg+2)
+	if beg == end || spikes[beg] != tStart {
+		res = append(res, tStart)
+	}
+	res = append(res, spikes[beg:end]...)
+	if res[len(res)-1] != tEnd {
+		res = append(res, tEnd)
+	}
+	return res
+}
+
+// events returns the sorted union of the spike times without duplicates.
+func events(x, y []float64) []float64 {
+	res := make([]float64, 0, len(x)+len(y))
+	var i, j int
+	for i < len(x) || j < len(y) {
+		var v float64
+		if j == len(y) || (i < len(x) && x[i] < y[j]) {
+			v, i = x[i], i+1
+		} else {
+			v, j = y[j], j+1
+		}
+		if len(res) == 0 || res[len(res)-1] != v {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// previous returns the index of the last spike at or before t. Requires
+// that spikes[0] <= t < spikes[len(spikes)-1].
+func previous(spikes []float64, t float64) int {
+	return sort.Search(len(spikes), func(i int) bool { return spikes[i] > t }) - 1
+}
+
+// nearest returns the distance of t to the nearest spike.
+func nearest(spikes []float64, t float64) float64 {
+	k := sort.SearchFloat64s(spikes, t)
+	d := math.Inf(1)
+	if k < len(spikes) {
+		d = spikes[k] - t
+	}
+	if k > 0 {
+		d = min(d, t-spikes[k-1])
+	}
+	return d
+}
+
+// ISIDistanceProfile returns the ISI-distance profile of the spike lines in
+// [tStart,tEnd]. At time t, it is the absolute difference of the current
+// inter-spike intervals of the lines divided by the largest one. The profile
+// is piecewise constant and its points are the ends of the constant segments.
+// The edges tStart and tEnd are considered as spikes of both lines. Returns
+// nil if tStart > tEnd.
+func ISIDistanceProfile(a, b plots.SpikeLine, tStart, tEnd float64) plotter.XYs {
+	if !(tStart <= tEnd) {
+		return nil
+	}
+	x := withEdges(a.Spikes, tStart, tEnd)
+	y := withEdges(b.Spikes, tStart, tEnd)
+	u := events(x, y)
+	profile := make(plotter.XYs, 0, 2*(len(u)-1))
+	for k := 0; k < len(u)-1; k++ {
+		i, j := previous(x, u[k]), previous(y, u[k])
+		xISI, yISI := x[i+1]-x[i], y[j+1]-y[j]
+		v := math.Abs(xISI-yISI) / max(xISI, yISI)
+		profile = append(profile, plotter.XY{X: u[k], Y: v}, plotter.XY{X: u[k+1], Y: v})
+	}
+	return profile
+}
+
+// SpikeDistanceProfile returns the SPIKE-distance profile of the spike lines
+// in [tStart,tEnd]. At time t, it is the mean distance of the spikes around
+// t in each line to the nearest spike of the other line, weighted by the
+// position of t in the intervals and normalized by the mean interval. The
+// profile is piecewise linear and its points are the ends of the linear
+// segments. The edges tStart and tEnd are considered as spikes of both lines.
+// Returns nil if tStart > tEnd.
+func SpikeDistanceProfile(a, b plots.SpikeLine, tStart, tEnd float64) plotter.XYs {
+	if !(tStart <= tEnd) {
+		return nil
+	}
+	x := withEdges(a.Spikes, tStart, tEnd)
+	y := withEdges(b.Spikes, tStart, tEnd)
+	u := events(x, y)
+
+	// local dissimilarity of the line spikes around t with the other line
+	local := func(spikes, other []float64, i int, t float64) float64 {
+		tP, tF := spikes[i], spikes[i+1]
+		dP, dF := nearest(other, tP), nearest(other, tF)
+		return (dP*(tF-t) + dF*(t-tP)) / (tF - tP)
+	}
+	profile := make(plotter.XYs, 0, 2*(len(u)-1))
+	for k := 0; k < len(u)-1; k++ {
+		i, j := previous(x, u[k]), previous(y, u[k])
+		xISI, yISI := x[i+1]-x[i], y[j+1]-y[j]
+		mean := (xISI + yISI) / 2
+		for _, t := range []float64{u[k], u[k+1]} {
+			s := (local(x, y, i, t)*yISI + local(y, x, j, t)*xISI) / (2 * mean * mean)
+			profile = append(profile, plotter.XY{X: t, Y: s})
+		}
+	}
+	return profile
+}
+
+// ISIDistance returns the time average of the ISI-distance profile of the
+// spike lines in [tStart,tEnd].
+func ISIDistance(a, b plots.SpikeLine, tStart, tEnd float64) float64 {
+	return average(ISIDistanceProfile(a, b, tStart, tEnd))
+}
+
+// SpikeDistance returns the time average of the SPIKE-distance profile of
+// the spike lines in [tStart,tEnd].
+func SpikeDistance(a, b plots.SpikeLine, tStart, tEnd float64) float64 {
+	return average(SpikeDistanceProfile(a, b, tStart, tEnd))
+}
+
+// average returns the time average of a profile made of linear segments
+// given by pairs of points.
+func average(profile plotter.XYs) float64 {
+	if len(profile) == 0 {
+		return 0
+	}
+	var sum float64
+	for k := 0; k < len(profile); k += 2 {
+		p0, p1 := profile[k], profile[k+1]
+		sum += (p1.X - p0.X) * (p0.Y + p1.Y) / 2
+	}
+	return sum / (profile[len(profile)-1].X - profile[0].X)
+}
+
+// SpikeSyncProfile returns the SPIKE-synchronization profile of the spike
+// lines in [tStart,tEnd]. It has a point per spike, in increasing time order,
+// with value 1 if the spike is coincident with a spike of the other line and
+// 0 otherwise. Two spikes are coincident when their time difference is less
+// than half of the smallest of their surrounding inter-spike intervals.
+// Returns nil if tStart > tEnd.
+func SpikeSyncProfile(a, b plots.SpikeLine, tStart, tEnd float64) plotter.XYs {
+	if !(tStart <= tEnd) {
+		return nil
+	}
+	x := withEdges(a.Spikes, tStart, tEnd)
+	y := withEdges(b.Spikes, tStart, tEnd)
+
+	// coincidence returns the points of the spikes of the line
+	coincidence := func(spikes, edged, other, otherEdged []float64) plotter.XYs {
+		beg := sort.SearchFloat64s(spikes, tStart)
+		end := sort.Search(len(spikes), func(i int) bool { return spikes[i] > tEnd })
+		points := make(plotter.XYs, 0, end-beg)
+		for _, t := range spikes[beg:end] {
+			p := plotter.XY{X: t}
+			if len(other) != 0 {
+				tau := localISI(edged, t)
+				k := sort.SearchFloat64s(other, t)
+				if k == len(other) || (k > 0 && t-other[k-1] < other[k]-t) {
+					k--
+				}
+				tau = min(tau, localISI(otherEdged, other[k])) / 2
+				if math.Abs(t-other[k]) < tau {
+					p.Y = 1
+				}
+			}
+			points = append(points, p)
+		}
+		return points
+	}
+	profile := append(coincidence(a.Spikes, x, b.Spikes, y), coincidence(b.Spikes, y, a.Spikes, x)...)
+	sort.SliceStable(profile, func(i, j int) bool { return profile[i].X < profile[j].X })
+	return profile
+}
+
+// localISI returns the smallest of the inter-spike intervals before and
+// after the spike t of the line with edges.
+func localISI(edged []float64, t float64) float64 {
+	k := sort.SearchFloat64s(edged, t)
+	d := math.Inf(1)
+	if k > 0 {
+		d = t - edged[k-1]
+	}
+	if k+1 < len(edged) {
+		d = min(d, edged[k+1]-t)
+	}
+	return d
+}
+
+// SpikeSync returns the SPIKE-synchronization of the spike lines in
+// [tStart,tEnd], the fraction of coincident spikes. Returns 1 when there
+// are no spikes.
+func SpikeSync(a, b plots.SpikeLine, tStart, tEnd float64) float64 {
+	profile := SpikeSyncProfile(a, b, tStart, tEnd)
+	if len(profile) == 0 {
+		return 1
+	}
+	var sum float64
+	for _, p := range profile {
+		sum += p.Y
+	}
+	return sum / float64(len(profile))
+}
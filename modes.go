@@ -0,0 +1,106 @@
+package plots
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// LineMode is the way the points of a line are connected.
+type LineMode int
+
+const (
+	StraightMode    LineMode = iota // Straight segments between the points.
+	StepPreMode                     // Steps going vertically first.
+	StepMidMode                     // Steps changing halfway between the points.
+	StepPostMode                    // Steps going horizontally first.
+	StemMode                        // Vertical stems from the baseline to the points.
+	AreaMode                        // Straight segments with the area to the baseline filled.
+	FillBetweenMode                 // Straight segments with the area to the FillTo points filled.
+)
+
+// stepKind returns the gonum step kind of the mode.
+func (m LineMode) stepKind() plotter.StepKind {
+	switch m {
+	case StepPreMode:
+		return plotter.PreStep
+	case StepMidMode:
+		return plotter.MidStep
+	case StepPostMode:
+		return plotter.PostStep
+	}
+	return plotter.NoStep
+}
+
+// fillPlotter returns the polygon filling the area between the line points
+// and the baseline, or the FillTo points.
+func fillPlotter(line Line, xys plotter.XYs) (*plotter.Polygon, error) {
+	var other plotter.XYs
+	if line.Mode == FillBetweenMode {
+		if line.FillTo == nil {
+			return nil, errors.New("fill between without FillTo points")
+		}
+		var err error
+		other, err = plotter.CopyXYs(line.FillTo)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(xys) != 0 {
+		other = plotter.XYs{{X: xys[0].X, Y: line.Baseline}, {X: xys[len(xys)-1].X, Y: line.Baseline}}
+	}
+	area := make(plotter.XYs, 0, len(xys)+len(other))
+	area = append(area, xys...)
+	for i := len(other) - 1; i >= 0; i-- {
+		area = append(area, other[i])
+	}
+	poly, err := plotter.NewPolygon(area)
+	if err != nil {
+		return nil, err
+	}
+	fillColor := line.FillColor
+	if fillColor == nil {
+		fillColor = line.Color
+	}
+	if fillColor == nil {
+		fillColor = line.GlyphColor
+	}
+	alpha := line.FillAlpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = 0.3
+	}
+	poly.Color = withAlpha(fillColor, uint8(math.Round(alpha*255)))
+	poly.LineStyle.Width = 0
+	return poly, nil
+}
+
+// stems is a plotter drawing vertical lines from the baseline to the points.
+type stems struct {
+	plotter.XYs
+	draw.LineStyle
+	baseline float64
+}
+
+// Plot draws the stems.
+func (s *stems) Plot(c draw.Canvas, p *plot.Plot) {
+	trX, trY := p.Transforms(&c)
+	for _, xy := range s.XYs {
+		x := trX(xy.X)
+		c.StrokeLines(s.LineStyle, c.ClipLinesXY([]vg.Point{{X: x, Y: trY(s.baseline)}, {X: x, Y: trY(xy.Y)}})...)
+	}
+}
+
+// DataRange returns the range of the points and of the baseline.
+func (s *stems) DataRange() (xmin, xmax, ymin, ymax float64) {
+	xmin, xmax, ymin, ymax = plotter.XYRange(s.XYs)
+	return xmin, xmax, min(ymin, s.baseline), max(ymax, s.baseline)
+}
+
+// Thumbnail draws a vertical stem in the legend.
+func (s *stems) Thumbnail(c *draw.Canvas) {
+	x := c.Center().X
+	c.StrokeLine2(s.LineStyle, x, c.Min.Y, x, c.Max.Y)
+}
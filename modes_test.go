@@ -0,0 +1,63 @@
+package plots
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestLineModePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	spikes := GeneratePoissonDistributedSpikes(2, 20, 0)
+	var counts plotter.XYs
+	for i, v := range spikes {
+		counts = append(counts, plotter.XY{X: v, Y: float64(i + 1)})
+	}
+	var median, low, high, samples plotter.XYs
+	for i := 0; i <= 40; i++ {
+		x := float64(i) / 20
+		median = append(median, plotter.XY{X: x, Y: 20 + 10*math.Sin(3*x)})
+		low = append(low, plotter.XY{X: x, Y: 14 + 8*math.Sin(3*x)})
+		high = append(high, plotter.XY{X: x, Y: 27 + 12*math.Sin(3*x)})
+		if i%4 == 0 {
+			samples = append(samples, plotter.XY{X: x, Y: 5 + 5*math.Cos(4*x)})
+		}
+	}
+	lines := Lines{
+		Title: "line modes",
+		Lines: []Line{
+			{Label: "quantiles", Points: low, Mode: FillBetweenMode, FillTo: high, Color: DarkColors.Id(3), Dashes: Dashes.Id(0)},
+			{Label: "median", Points: median, Color: DarkColors.Id(3)},
+			{Label: "cumulative count", Points: counts, Color: DarkColors.Id(1), Mode: StepPostMode},
+			{Label: "area", Points: samples, Color: DarkColors.Id(2), Mode: AreaMode, FillAlpha: 0.2},
+			{Label: "stems", Points: samples, Color: DarkColors.Id(4), Mode: StemMode},
+		},
+	}
+	if err := MakeLinePlot(lines, "tests/lineModePlot.png", "tests/lineModePlot.svg"); err != nil {
+		t.Fatal(err)
+	}
+
+	ps, _, err := linePlotters(Line{Points: samples, Glyph: Glyphs.Id(1), GlyphColor: DarkColors.Id(1), Mode: StemMode}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, p := range ps {
+		if s, ok := p.(*stems); ok {
+			found = true
+			if s.Width == 0 || s.Color != DarkColors.Id(1) {
+				t.Errorf("got stem width %v and color %v, expected visible stems with the glyph color", s.Width, s.Color)
+			}
+		}
+	}
+	if !found {
+		t.Error("no stems plotter")
+	}
+
+	lines.Lines[0].FillTo = nil
+	if _, err := NewLinePlot(lines); err == nil {
+		t.Error("expected an error for fill between without FillTo points")
+	}
+}
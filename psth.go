@@ -0,0 +1,274 @@
+package plots
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Smoothing is a smoothing kernel type.
+type Smoothing int
+
+const (
+	NoSmoothing       Smoothing = iota // No smoothing.
+	BoxcarSmoothing                    // Moving average of kernel width.
+	GaussianSmoothing                  // Gaussian with kernel width as standard deviation.
+)
+
+// PSTH is a peri-stimulus time histogram of the spikes of spike lines.
+type PSTH struct {
+	Title     string      // Title.
+	Lines     []SpikeLine // Spike lines to bin.
+	Select    []int       // Index of the lines to bin, all lines if nil.
+	XLimit    *Limit      // Spike time range limit.
+	Epochs    Epochs      // Time intervals drawn as bands behind the bars.
+	BinWidth  float64     // Bin width (default = time range / 50).
+	Smoothing Smoothing   // Smoothing kernel (default = NoSmoothing).
+	Kernel    float64     // Smoothing kernel width (default = BinWidth).
+	Rate      bool        // Normalize bin counts to rates in Hz per line.
+	Unit      TimeUnit    // Spike time unit (default = Seconds).
+	Color     color.Color // Bar fill color (default = grey).
+	LColor    color.Color // Bar outline color (default = black).
+	LWidth    vg.Length   // Bar outline width (default = vg.Points(1)).
+	SColor    color.Color // Smoothed curve color (default = red).
+	SWidth    vg.Length   // Smoothed curve width (default = vg.Points(1.5)).
+	XDim      vg.Length   // X dimension of saved plot, use default if 0.
+	YDim      vg.Length   // Y dimension of saved plot, use default if 0.
+}
+
+// lines returns the selected spike lines.
+func (h PSTH) lines() []SpikeLine {
+	if h.Select == nil {
+		return h.Lines
+	}
+	lines := make([]SpikeLine, 0, len(h.Select))
+	for _, i := range h.Select {
+		lines = append(lines, h.Lines[i])
+	}
+	return lines
+}
+
+// xRange returns the XLimit values, or the min and max spike time values of
+// the selected lines when XLimit is nil.
+func (h PSTH) xRange() (xMin, xMax float64) {
+	if h.XLimit != nil {
+		return h.XLimit.Min, h.XLimit.Max
+	}
+	return spikesRange(h.lines())
+}
+
+// Bins returns the histogram bins of the selected spike lines in the time
+// range [xMin,xMax], with the spikes at xMax in the last bin. The bin weights are the spike counts, or the firing
+// rate in Hz per line when Rate is true. The rate is per time unit when the
+// unit duration is unknown. Returns nil when the range is empty.
+func (h PSTH) Bins(xMin, xMax float64) []plotter.HistogramBin {
+	if !(xMax > xMin) || math.IsInf(xMax-xMin, 0) {
+		return nil
+	}
+	binWidth := h.BinWidth
+	if binWidth <= 0 {
+		binWidth = (xMax - xMin) / 50
+	}
+	lines := h.lines()
+	nBins := int(math.Ceil((xMax - xMin) / binWidth))
+	bins := make([]plotter.HistogramBin, nBins)
+	for i := range bins {
+		bins[i].Min = xMin + float64(i)*binWidth
+		bins[i].Max = min(bins[i].Min+binWidth, xMax)
+	}
+	for i := range lines {
+		spikes := lines[i].Spikes
+		beg := sort.SearchFloat64s(spikes, xMin)
+		end := sort.Search(len(spikes), func(k int) bool { return spikes[k] > xMax })
+		for _, v := range spikes[beg:max(beg, end)] {
+			bins[min(int((v-xMin)/binWidth), nBins-1)].Weight++
+		}
+	}
+	if h.Rate && len(lines) != 0 {
+		for i := range bins {
+			bins[i].Weight *= h.Unit.perSecond() / ((bins[i].Max - bins[i].Min) * float64(len(lines)))
+		}
+	}
+	return bins
+}
+
+// Smoothed returns the bin centers and smoothed weights of the bins.
+func (h PSTH) Smoothed(bins []plotter.HistogramBin) plotter.XYs {
+	if len(bins) == 0 {
+		return nil
+	}
+	binWidth := bins[0].Max - bins[0].Min
+	kernel := h.Kernel
+	if kernel <= 0 {
+		kernel = binWidth
+	}
+	values := make([]float64, len(bins))
+	for i := range bins {
+		values[i] = bins[i].Weight
+	}
+	values = smooth(values, h.Smoothing, kernel/binWidth)
+	xys := make(plotter.XYs, len(bins))
+	for i := range bins {
+		xys[i].X = (bins[i].Min + bins[i].Max) / 2
+		xys[i].Y = values[i]
+	}
+	return xys
+}
+
+// smooth returns the values smoothed with the given kernel whose width is
+// expressed in number of values. The boxcar kernel averages width values,
+// centered on the value or just after it for an even width. The kernel is
+// normalized at the borders.
+func smooth(values []float64, s Smoothing, width float64) []float64 {
+	var weights []float64
+	switch s {
+	case BoxcarSmoothing:
+		weights = make([]float64, max(int(math.Round(width)), 1))
+		for i := range weights {
+			weights[i] = 1
+		}
+	case GaussianSmoothing:
+		n := max(int(math.Ceil(3*width)), 0)
+		weights = make([]float64, 2*n+1)
+		for i := range weights {
+			d := float64(i-n) / width
+			weights[i] = math.Exp(-d * d / 2)
+		}
+	default:
+		return append([]float64(nil), values...)
+	}
+	n := len(weights) / 2
+	res := make([]float64, len(values))
+	for i := range values {
+		var sum, norm float64
+		for j, w := range weights {
+			k := i + j - n
+			if k < 0 || k >= len(values) {
+				continue
+			}
+			sum += w * values[k]
+			norm += w
+		}
+		res[i] = sum / norm
+	}
+	return res
+}
+
+// newPSTHPlot returns the plot of the PSTH in the time range [xMin,xMax].
+func newPSTHPlot(h PSTH, xMin, xMax float64) *plot.Plot {
+	p := plot.New()
+	p.Title.Text = h.Title
+	p.X.Label.Text = h.Unit.timeLabel()
+	p.X.Tick.Marker = timeTicks{unit: h.Unit}
+	if h.Rate {
+		p.Y.Label.Text = h.Unit.rateLabel()
+	} else {
+		p.Y.Label.Text = "Count"
+	}
+	p.X.Min, p.X.Max = xMin, xMax
+	if len(h.Epochs) != 0 {
+		p.Add(h.Epochs)
+	}
+	bins := h.Bins(xMin, xMax)
+	if len(bins) == 0 {
+		return p
+	}
+	hist := &plotter.Histogram{
+		Bins:      bins,
+		Width:     bins[0].Max - bins[0].Min,
+		FillColor: color.RGBA{160, 160, 160, 255},
+		LineStyle: draw.LineStyle{
+			Color: color.RGBA{0, 0, 0, 255},
+			Width: vg.Points(1),
+		},
+	}
+	if h.Color != nil {
+		hist.FillColor = h.Color
+	}
+	if h.LColor != nil {
+		hist.LineStyle.Color = h.LColor
+	}
+	if h.LWidth != 0 {
+		hist.LineStyle.Width = h.LWidth
+	}
+	p.Add(hist)
+	if h.Smoothing != NoSmoothing {
+		l := &plotter.Line{
+			XYs: h.Smoothed(bins),
+			LineStyle: draw.LineStyle{
+				Color: color.RGBA{238, 46, 47, 255},
+				Width: vg.Points(1.5),
+			},
+		}
+		if h.SColor != nil {
+			l.LineStyle.Color = h.SColor
+		}
+		if h.SWidth != 0 {
+			l.LineStyle.Width = h.SWidth
+		}
+		p.Add(l)
+	}
+	return p
+}
+
+// MakePSTHPlot generates the peri-stimulus time histogram plot.
+func MakePSTHPlot(h PSTH, fileNames ...string) error {
+	xMin, xMax := h.xRange()
+	p := newPSTHPlot(h, xMin, xMax)
+	xDim, yDim := plotDims(h.XDim, h.YDim)
+	for _, fileName := range fileNames {
+		err := p.Save(xDim, yDim, fileName)
+		if err != nil {
+			return fmt.Errorf("psth plot: %w", err)
+		}
+	}
+	return nil
+}
+
+// MakeSpikePSTHPlot generates the spike plot with the peri-stimulus time
+// histogram stacked below it. Both plots share the time range of the spike
+// plot. The PSTH bins the spike lines of the spike plot when h.Lines is nil,
+// and shows the unlabeled epochs of the spike plot when h.Epochs is nil.
+// The PSTH XLimit, Title, XDim and YDim are ignored.
+func MakeSpikePSTHPlot(spikeLines SpikeLines, h PSTH, fileNames ...string) error {
+	if err := spikeLines.checkUnits(); err != nil {
+		return fmt.Errorf("spike psth plot: %w", err)
+	}
+	top, bottom := newSpikePSTHPlots(spikeLines, h)
+	xDim, yDim := plotDims(spikeLines.XDim, spikeLines.YDim)
+	err := saveStacked([]panel{top, bottom}, []float64{3, 1}, xDim, yDim, fileNames...)
+	if err != nil {
+		return fmt.Errorf("spike psth plot: %w", err)
+	}
+	return nil
+}
+
+// newSpikePSTHPlots returns the spike plot and the PSTH plot below it with
+// the same time range.
+func newSpikePSTHPlots(spikeLines SpikeLines, h PSTH) (*SpikePlot, *plot.Plot) {
+	spikeLines = spikeLines.converted()
+	h.Unit = spikeLines.Unit
+	if h.Lines == nil {
+		h.Lines = spikeLines.Lines
+	}
+	if h.Epochs == nil {
+		// epoch labels are only drawn in the spike plot
+		h.Epochs = make(Epochs, len(spikeLines.Epochs))
+		for i, e := range spikeLines.Epochs {
+			e.Label = ""
+			h.Epochs[i] = e
+		}
+	}
+	h.Title = ""
+	top := NewSpikePlot(spikeLines)
+	top.X.Label.Text = ""
+	bottom := newPSTHPlot(h, top.X.Min, top.X.Max)
+	bottom.X.Label.Text = spikeLines.xLabel()
+	return top, bottom
+}
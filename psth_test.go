@@ -0,0 +1,83 @@
+package plots
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestPSTHBins(t *testing.T) {
+	h := PSTH{
+		Lines: []SpikeLine{
+			{Spikes: []float64{0, 0.1, 0.5, 0.9}},
+			{Spikes: []float64{0.2, 1, 1.5}},
+		},
+		BinWidth: 0.5,
+	}
+	bins := h.Bins(0, 1)
+	if len(bins) != 2 {
+		t.Fatalf("got %d bins, expected 2", len(bins))
+	}
+	if bins[0].Weight != 3 || bins[1].Weight != 3 {
+		t.Fatalf("got counts %v and %v, expected 3 and 3", bins[0].Weight, bins[1].Weight)
+	}
+	h.Rate = true
+	bins = h.Bins(0, 1)
+	if bins[0].Weight != 3 || bins[1].Weight != 3 {
+		t.Fatalf("got rates %v and %v, expected 3 and 3", bins[0].Weight, bins[1].Weight)
+	}
+	h.Select = []int{1}
+	bins = h.Bins(0, 1)
+	if bins[0].Weight != 2 || bins[1].Weight != 2 {
+		t.Fatalf("got rates %v and %v, expected 2 and 2", bins[0].Weight, bins[1].Weight)
+	}
+	h.Select = nil
+	if bins = h.Bins(h.xRange()); bins[len(bins)-1].Weight == 0 {
+		t.Fatalf("got last bin rate 0, expected the latest spike counted")
+	}
+}
+
+func TestBoxcarSmoothing(t *testing.T) {
+	values := []float64{0, 0, 3, 0, 0}
+	if got := smooth(values, BoxcarSmoothing, 1); fmt.Sprint(got) != "[0 0 3 0 0]" {
+		t.Errorf("got %v with a kernel of 1 bin, expected the values", got)
+	}
+	if got := smooth(values, BoxcarSmoothing, 3); fmt.Sprint(got) != "[0 1 1 1 0]" {
+		t.Errorf("got %v with a kernel of 3 bins, expected [0 1 1 1 0]", got)
+	}
+}
+
+func TestSpikePSTHPlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	duration := 4.
+	var spikes SpikeLines
+	for i := 0; i < 20; i++ {
+		spikes.Lines = append(spikes.Lines, SpikeLine{
+			Label:  fmt.Sprintf("%d", i+1),
+			Spikes: GeneratePoissonDistributedSpikes(duration, 10, 0.02),
+		})
+	}
+	spikes.Title = "Spikes with PSTH"
+	spikes.Epochs = Epochs{
+		{Start: 1, End: 1.5, Label: "stim A"},
+		{Start: 2.5, End: 3, Label: "stim B", Color: DarkColors.Id(2)},
+	}
+	h := PSTH{
+		BinWidth:  0.05,
+		Smoothing: GaussianSmoothing,
+		Kernel:    0.1,
+		Rate:      true,
+	}
+	err := MakeSpikePSTHPlot(spikes, h, "tests/spikePSTHPlot.png", "tests/spikePSTHPlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	h.Lines = spikes.Lines
+	h.Title = "PSTH"
+	h.Smoothing = BoxcarSmoothing
+	h.XLimit = &Limit{Min: 1, Max: 3}
+	err = MakePSTHPlot(h, "tests/psthPlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+}
@@ -0,0 +1,281 @@
+package plots
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// makeSpikeLines returns the spike lines of the (neuron id, spike time)
+// pairs. There is one line per neuron id, in increasing id order, labeled
+// with the id and with its spike times sorted in increasing order.
+func makeSpikeLines(ids []int64, times []float64) SpikeLines {
+	spikes := make(map[int64][]float64)
+	for i, id := range ids {
+		spikes[id] = append(spikes[id], times[i])
+	}
+	sortedIds := make([]int64, 0, len(spikes))
+	for id := range spikes {
+		sortedIds = append(sortedIds, id)
+	}
+	sort.Slice(sortedIds, func(i, j int) bool { return sortedIds[i] < sortedIds[j] })
+	var s SpikeLines
+	s.Lines = make([]SpikeLine, len(sortedIds))
+	for i, id := range sortedIds {
+		sort.Float64s(spikes[id])
+		s.Lines[i] = SpikeLine{
+			Label:  strconv.FormatInt(id, 10),
+			Spikes: spikes[id],
+		}
+	}
+	return s
+}
+
+// readIdTimeRows reads the rows of (neuron id, spike time) pairs whose fields
+// are separated by sep, or by white spaces if sep is 0. Empty rows and rows
+// starting with # are ignored, as well as a header row if it is the first one.
+func readIdTimeRows(r io.Reader, sep rune) (ids []int64, times []float64, err error) {
+	scanner := bufio.NewScanner(r)
+	var lineNum int
+	var hasRow bool
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var fields []string
+		if sep == 0 {
+			fields = strings.Fields(line)
+		} else {
+			fields = strings.Split(line, string(sep))
+		}
+		if len(fields) < 2 {
+			return nil, nil, fmt.Errorf("line %d: expected 2 fields, got %d", lineNum, len(fields))
+		}
+		id, errId := strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
+		time, errTime := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if !hasRow && errId != nil && errTime != nil {
+			// skip the header row
+			hasRow = true
+			continue
+		}
+		hasRow = true
+		if errId != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid neuron id %q", lineNum, fields[0])
+		}
+		if errTime != nil || math.IsNaN(time) {
+			return nil, nil, fmt.Errorf("line %d: invalid spike time %q", lineNum, fields[1])
+		}
+		ids = append(ids, id)
+		times = append(times, time)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return ids, times, nil
+}
+
+// ReadNEST reads the spike lines of a NEST spike recorder .gdf or .dat file
+// with white space separated (sender, time) rows. The NEST spike times are
+// in ms, which is the Unit of the spike lines. The comment and header rows
+// are ignored.
+func ReadNEST(r io.Reader) (SpikeLines, error) {
+	ids, times, err := readIdTimeRows(r, 0)
+	if err != nil {
+		return SpikeLines{}, fmt.Errorf("read nest: %w", err)
+	}
+	s := makeSpikeLines(ids, times)
+	s.Unit = Milliseconds
+	return s, nil
+}
+
+// ReadCSV reads the spike lines of a comma separated (index, time) rows file,
+// as exported from the Brian2 SpikeMonitor i and t values. An optional header
+// row is ignored.
+func ReadCSV(r io.Reader) (SpikeLines, error) {
+	ids, times, err := readIdTimeRows(r, ',')
+	if err != nil {
+		return SpikeLines{}, fmt.Errorf("read csv: %w", err)
+	}
+	return makeSpikeLines(ids, times), nil
+}
+
+// ReadNPY reads the spike lines of a pair of NumPy .npy one dimensional
+// arrays of neuron indexes and spike times, as saved from the Brian2
+// SpikeMonitor i and t values. Integer and float arrays are supported.
+func ReadNPY(index, time io.Reader) (SpikeLines, error) {
+	indexValues, err := readNPY(index)
+	if err != nil {
+		return SpikeLines{}, fmt.Errorf("read npy index: %w", err)
+	}
+	times, err := readNPY(time)
+	if err != nil {
+		return SpikeLines{}, fmt.Errorf("read npy time: %w", err)
+	}
+	if len(indexValues) != len(times) {
+		return SpikeLines{}, fmt.Errorf("read npy: %d indexes and %d times", len(indexValues), len(times))
+	}
+	ids := make([]int64, len(indexValues))
+	for i, v := range indexValues {
+		if v != math.Trunc(v) {
+			return SpikeLines{}, fmt.Errorf("read npy index: value %d: invalid neuron index %v", i, v)
+		}
+		ids[i] = int64(v)
+		if math.IsNaN(times[i]) {
+			return SpikeLines{}, fmt.Errorf("read npy time: value %d: invalid spike time", i)
+		}
+	}
+	return makeSpikeLines(ids, times), nil
+}
+
+// ReadNESTFile reads the spike lines of a NEST spike recorder file.
+func ReadNESTFile(fileName string) (SpikeLines, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return SpikeLines{}, err
+	}
+	defer f.Close()
+	return ReadNEST(f)
+}
+
+// ReadCSVFile reads the spike lines of an (index, time) CSV file.
+func ReadCSVFile(fileName string) (SpikeLines, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return SpikeLines{}, err
+	}
+	defer f.Close()
+	return ReadCSV(f)
+}
+
+// ReadNPYFiles reads the spike lines of a pair of index and time .npy files.
+func ReadNPYFiles(indexFileName, timeFileName string) (SpikeLines, error) {
+	index, err := os.Open(indexFileName)
+	if err != nil {
+		return SpikeLines{}, err
+	}
+	defer index.Close()
+	time, err := os.Open(timeFileName)
+	if err != nil {
+		return SpikeLines{}, err
+	}
+	defer time.Close()
+	return ReadNPY(index, time)
+}
+
+var (
+	npyMagic   = []byte("\x93NUMPY")
+	npyDescr   = regexp.MustCompile(`'descr'\s*:\s*'([<>|=])([iuf])(\d+)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape'\s*:\s*\(\s*(\d+)\s*,?\s*\)`)
+)
+
+// readNPY returns the values of a one dimensional .npy array.
+func readNPY(r io.Reader) ([]float64, error) {
+	var preamble [8]byte
+	if _, err := io.ReadFull(r, preamble[:]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(preamble[:6], npyMagic) {
+		return nil, errors.New("not a npy file")
+	}
+	var headerLen int
+	switch preamble[6] {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		headerLen = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		headerLen = int(n)
+	default:
+		return nil, fmt.Errorf("unsupported npy version %d", preamble[6])
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	descr := npyDescr.FindSubmatch(header)
+	if descr == nil {
+		return nil, fmt.Errorf("unsupported npy data type in header %q", header)
+	}
+	if m := npyFortran.FindSubmatch(header); m == nil || string(m[1]) == "True" {
+		return nil, errors.New("unsupported npy fortran order")
+	}
+	shape := npyShape.FindSubmatch(header)
+	if shape == nil {
+		return nil, fmt.Errorf("unsupported npy shape in header %q", header)
+	}
+	n, err := strconv.Atoi(string(shape[1]))
+	if err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if descr[1][0] == '>' {
+		order = binary.BigEndian
+	}
+	kind := descr[2][0]
+	size, _ := strconv.Atoi(string(descr[3]))
+	switch {
+	case kind == 'f' && (size == 4 || size == 8):
+	case (kind == 'i' || kind == 'u') && (size == 1 || size == 2 || size == 4 || size == 8):
+	default:
+		return nil, fmt.Errorf("unsupported npy data type %s", descr[0])
+	}
+	if n > (math.MaxInt-1)/size {
+		return nil, fmt.Errorf("npy shape %d too large", n)
+	}
+	// read the data without trusting the shape to allocate the buffer
+	data, err := io.ReadAll(io.LimitReader(r, int64(n*size)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != n*size {
+		return nil, fmt.Errorf("npy data has %d bytes, expected %d for shape %d", len(data), n*size, n)
+	}
+	values := make([]float64, n)
+	for i := range values {
+		b := data[i*size : (i+1)*size]
+		var u uint64
+		switch size {
+		case 1:
+			u = uint64(b[0])
+		case 2:
+			u = uint64(order.Uint16(b))
+		case 4:
+			u = uint64(order.Uint32(b))
+		case 8:
+			u = order.Uint64(b)
+		}
+		switch kind {
+		case 'f':
+			if size == 4 {
+				values[i] = float64(math.Float32frombits(uint32(u)))
+			} else {
+				values[i] = math.Float64frombits(u)
+			}
+		case 'i':
+			// sign extend the value
+			shift := 64 - 8*size
+			values[i] = float64(int64(u<<shift) >> shift)
+		case 'u':
+			values[i] = float64(u)
+		}
+	}
+	return values, nil
+}
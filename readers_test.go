@@ -0,0 +1,91 @@
+package plots
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadNEST(t *testing.T) {
+	data := "# NEST version: 3.6\nsender\ttime_ms\n12\t5.5\n3\t1.0\n12\t2.5\n\n3\t0.5\n"
+	s, err := ReadNEST(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Lines) != 2 {
+		t.Fatalf("got %d lines, expected 2", len(s.Lines))
+	}
+	if s.Lines[0].Label != "3" || fmt.Sprint(s.Lines[0].Spikes) != "[0.5 1]" {
+		t.Fatalf("got line %q %v, expected line \"3\" [0.5 1]", s.Lines[0].Label, s.Lines[0].Spikes)
+	}
+	if s.Lines[1].Label != "12" || fmt.Sprint(s.Lines[1].Spikes) != "[2.5 5.5]" {
+		t.Fatalf("got line %q %v, expected line \"12\" [2.5 5.5]", s.Lines[1].Label, s.Lines[1].Spikes)
+	}
+	if s.Unit != Milliseconds {
+		t.Errorf("got unit %v, expected ms", s.Unit)
+	}
+	_, err = ReadNEST(strings.NewReader("1 0.5\n2 x\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("got error %v, expected invalid spike time error on line 2", err)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	s, err := ReadCSV(strings.NewReader("i,t\n0,0.25\n1,0.5\n0,0.125\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Lines) != 2 || fmt.Sprint(s.Lines[0].Spikes) != "[0.125 0.25]" {
+		t.Fatalf("got lines %v, expected 2 lines with first spikes [0.125 0.25]", s.Lines)
+	}
+	_, err = ReadCSV(strings.NewReader("0,0.25\n1\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("got error %v, expected missing field error on line 2", err)
+	}
+}
+
+// makeNPY returns a version 1 .npy file of the values.
+func makeNPY(descr string, values any, n int) []byte {
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%d,), }", descr, n)
+	for (10+len(header)+1)%64 != 0 {
+		header += " "
+	}
+	header += "\n"
+	var buf bytes.Buffer
+	buf.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	binary.Write(&buf, binary.LittleEndian, values)
+	return buf.Bytes()
+}
+
+func TestReadNPY(t *testing.T) {
+	index := makeNPY("<i4", []int32{2, 0, 2}, 3)
+	time := makeNPY("<f8", []float64{0.3, 0.2, 0.1}, 3)
+	s, err := ReadNPY(bytes.NewReader(index), bytes.NewReader(time))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Lines) != 2 || s.Lines[1].Label != "2" || fmt.Sprint(s.Lines[1].Spikes) != "[0.1 0.3]" {
+		t.Fatalf("got lines %v, expected 2 lines with second \"2\" [0.1 0.3]", s.Lines)
+	}
+	time = makeNPY("<f4", []float32{0.5, 0.25}, 2)
+	_, err = ReadNPY(bytes.NewReader(index), bytes.NewReader(time))
+	if err == nil {
+		t.Fatal("expected size mismatch error")
+	}
+	_, err = ReadNPY(strings.NewReader("not npy data"), bytes.NewReader(time))
+	if err == nil {
+		t.Fatal("expected invalid npy file error")
+	}
+	for _, n := range []int{4, 1 << 60, math.MaxInt} {
+		time = makeNPY("<f8", []float64{0.3, 0.2, 0.1}, n)
+		_, err = ReadNPY(bytes.NewReader(index), bytes.NewReader(time))
+		if err == nil {
+			t.Fatalf("expected shape %d error", n)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package plots
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Recorder records the spikes of a running simulation to render spike plots
+// while it runs. Only the spikes in the sliding time window ending at the
+// current simulation time are kept. Its methods are safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	window  float64
+	now     float64
+	labels  []string
+	index   map[string]int
+	spikes  [][]float64
+	version uint64
+}
+
+// NewRecorder returns a recorder keeping the spikes in the given sliding
+// time window, or all spikes if window is 0. The spike lines are in the
+// order of the labels, followed by the lines of the other labels in the
+// order of their first spike. Each snapshot copies the spikes in the window,
+// so that with a window of 0 and no template XLimit, the cost of a snapshot
+// grows with the number of recorded spikes.
+func NewRecorder(window float64, labels ...string) *Recorder {
+	r := &Recorder{
+		window: window,
+		index:  make(map[string]int),
+	}
+	for _, label := range labels {
+		r.line(label)
+	}
+	return r
+}
+
+// line returns the index of the spike line with the label, and adds it if
+// needed. Requires that the mutex is locked.
+func (r *Recorder) line(label string) int {
+	i, ok := r.index[label]
+	if !ok {
+		i = len(r.labels)
+		r.index[label] = i
+		r.labels = append(r.labels, label)
+		r.spikes = append(r.spikes, nil)
+	}
+	return i
+}
+
+// Add records the spikes of the line with the label. The spikes may be out
+// of order and the current time advances to the latest spike.
+func (r *Recorder) Add(label string, spikes ...float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.line(label)
+	for _, v := range spikes {
+		if r.window > 0 && v < r.now-r.window {
+			continue
+		}
+		l := r.spikes[i]
+		if len(l) == 0 || v >= l[len(l)-1] {
+			r.spikes[i] = append(l, v)
+		} else {
+			k := sort.SearchFloat64s(l, v)
+			l = append(l, 0)
+			copy(l[k+1:], l[k:])
+			l[k] = v
+			r.spikes[i] = l
+		}
+		r.now = max(r.now, v)
+	}
+	r.trimLine(i)
+	r.version++
+}
+
+// SetTime advances the current simulation time to t, which slides the time
+// window when no spike occurs. Times before the current time are ignored.
+func (r *Recorder) SetTime(t float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t > r.now {
+		r.now = t
+		r.trim()
+		r.version++
+	}
+}
+
+// trim drops the spikes before the time window. Requires that the mutex is
+// locked.
+func (r *Recorder) trim() {
+	for i := range r.spikes {
+		r.trimLine(i)
+	}
+}
+
+// trimLine drops the spikes of line i before the time window. The spike
+// slice is compacted when less than half of its capacity is used, so that
+// the memory is proportional to the number of spikes in the window.
+// Requires that the mutex is locked.
+func (r *Recorder) trimLine(i int) {
+	if r.window <= 0 {
+		return
+	}
+	l := r.spikes[i]
+	k := sort.SearchFloat64s(l, r.now-r.window)
+	if k == 0 {
+		return
+	}
+	l = l[k:]
+	if len(l) < cap(l)/2 {
+		l = append(make([]float64, 0, 2*len(l)), l...)
+	}
+	r.spikes[i] = l
+}
+
+// Snapshot returns a copy of the template spike lines with the recorded
+// spike lines, and with the XLimit set to the time window. Without time
+// window, only the spikes in the template XLimit are copied if not nil. The
+// lines take the properties of the template line with the same label if any.
+func (r *Recorder) Snapshot(template SpikeLines) SpikeLines {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(template)
+}
+
+// snapshot returns the snapshot. Requires that the mutex is locked.
+func (r *Recorder) snapshot(template SpikeLines) SpikeLines {
+	r.trim()
+	properties := make(map[string]*SpikeLine, len(template.Lines))
+	for i := range template.Lines {
+		properties[template.Lines[i].Label] = &template.Lines[i]
+	}
+	s := template
+	s.Lines = make([]SpikeLine, len(r.labels))
+	for i, label := range r.labels {
+		var l SpikeLine
+		if p := properties[label]; p != nil {
+			l = *p
+			l.Categories = nil
+		}
+		l.Label = label
+		spikes := r.spikes[i]
+		if r.window <= 0 && template.XLimit != nil {
+			beg := sort.SearchFloat64s(spikes, template.XLimit.Min)
+			end := sort.Search(len(spikes), func(k int) bool { return spikes[k] > template.XLimit.Max })
+			spikes = spikes[beg:max(beg, end)]
+		}
+		l.Spikes = append([]float64(nil), spikes...)
+		s.Lines[i] = l
+	}
+	if r.window > 0 {
+		s.XLimit = &Limit{Min: r.now - r.window, Max: r.now}
+	}
+	return s
+}
+
+// Render calls render with a snapshot of the recorder every period, until
+// the context is done. Periods without new spikes or time change are
+// skipped. A last snapshot is rendered when the context is done. Returns
+// the first render error, or an error if the period is not positive.
+func (r *Recorder) Render(ctx context.Context, period time.Duration, template SpikeLines, render func(s SpikeLines) error) error {
+	if period <= 0 {
+		return fmt.Errorf("recorder: non-positive render period %v", period)
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	var rendered uint64
+	renderChanged := func() error {
+		r.mu.Lock()
+		if r.version == rendered {
+			r.mu.Unlock()
+			return nil
+		}
+		rendered = r.version
+		s := r.snapshot(template)
+		r.mu.Unlock()
+		return render(s)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return renderChanged()
+		case <-ticker.C:
+			if err := renderChanged(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RenderFiles renders the spike plot of the recorder in the files every
+// period, until the context is done, as Render does. Each file is replaced
+// atomically so that viewers never read a partially written file. Returns an
+// error if the period is not positive.
+func (r *Recorder) RenderFiles(ctx context.Context, period time.Duration, template SpikeLines, fileNames ...string) error {
+	return r.Render(ctx, period, template, func(s SpikeLines) error {
+		for _, fileName := range fileNames {
+			tmpName := filepath.Join(filepath.Dir(fileName), ".tmp-"+filepath.Base(fileName))
+			if err := MakeSpikePlot(s, tmpName); err != nil {
+				os.Remove(tmpName)
+				return err
+			}
+			if err := os.Rename(tmpName, fileName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
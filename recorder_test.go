@@ -0,0 +1,101 @@
+package plots
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(1, "b")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				r.Add("a", float64(k)*0.01+float64(i)*0.001)
+			}
+		}(i)
+	}
+	wg.Wait()
+	s := r.Snapshot(SpikeLines{Lines: []SpikeLine{{Label: "a", ZIndex: 1}}})
+	if len(s.Lines) != 2 || s.Lines[0].Label != "b" || s.Lines[1].ZIndex != 1 {
+		t.Fatalf("got lines %v", s.Lines)
+	}
+	if n := len(s.Lines[1].Spikes); n != 400 {
+		t.Errorf("got %d spikes, expected 400", n)
+	}
+	for k := 1; k < len(s.Lines[1].Spikes); k++ {
+		if s.Lines[1].Spikes[k] < s.Lines[1].Spikes[k-1] {
+			t.Fatalf("spikes are not sorted")
+		}
+	}
+	r.SetTime(1.5)
+	s = r.Snapshot(SpikeLines{})
+	if s.Lines[1].Spikes[0] < 0.5 || *s.XLimit != (Limit{Min: 0.5, Max: 1.5}) {
+		t.Errorf("got first spike %v and limit %v", s.Lines[1].Spikes[0], *s.XLimit)
+	}
+	r.Add("a", 0.2)
+	if s = r.Snapshot(SpikeLines{}); s.Lines[1].Spikes[0] < 0.5 {
+		t.Errorf("got spike %v before the time window", s.Lines[1].Spikes[0])
+	}
+
+	r = NewRecorder(0)
+	r.Add("a", 0, 1, 2, 3, 4, 5)
+	s = r.Snapshot(SpikeLines{XLimit: &Limit{Min: 2, Max: 4}})
+	if spikes := s.Lines[0].Spikes; !slices.Equal(spikes, []float64{2, 3, 4}) {
+		t.Errorf("got spikes %v, expected the spikes in the XLimit [2 3 4]", spikes)
+	}
+	if s = r.Snapshot(SpikeLines{}); len(s.Lines[0].Spikes) != 6 {
+		t.Errorf("got spikes %v, expected all the spikes", s.Lines[0].Spikes)
+	}
+}
+
+func TestRecorderRender(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	r := NewRecorder(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	var frames int
+	go func() {
+		done <- r.Render(ctx, time.Millisecond, SpikeLines{}, func(s SpikeLines) error {
+			frames++
+			return nil
+		})
+	}()
+	go func() {
+		done <- r.RenderFiles(ctx, 10*time.Millisecond, SpikeLines{Title: "Live spikes"}, "tests/recorderSpikePlot.png")
+	}()
+	for i := 0; i < 10; i++ {
+		// spikes of the next half second
+		for k := 0; k < 10; k++ {
+			spikes := GeneratePoissonDistributedSpikes(0.5, 10, 0.002)
+			for j := range spikes {
+				spikes[j] += float64(i) * 0.5
+			}
+			r.Add(fmt.Sprintf("%d", k+1), spikes...)
+		}
+		r.SetTime(float64(i+1) * 0.5)
+		time.Sleep(2 * time.Millisecond)
+	}
+	cancel()
+	for range 2 {
+		if err := <-done; err != nil {
+			t.Fatalf("render failed: %s", err)
+		}
+	}
+	if frames == 0 {
+		t.Errorf("no frame rendered")
+	}
+	if _, err := os.Stat("tests/recorderSpikePlot.png"); err != nil {
+		t.Errorf("plot not rendered: %s", err)
+	}
+	if err := r.RenderFiles(context.Background(), 0, SpikeLines{}, "tests/recorderSpikePlot.png"); err == nil {
+		t.Errorf("expected an error for a null period")
+	}
+}
@@ -0,0 +1,66 @@
+package plots
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Drawer is a plot drawn in a canvas, like *plot.Plot and *SpikePlot.
+type Drawer interface {
+	Draw(c draw.Canvas)
+}
+
+// Write writes the plot of the given size to w in the given format, like
+// "png", "svg", "pdf", "eps", "jpg" or "tiff". The default size is used
+// when a dimension is 0.
+func Write(w io.Writer, d Drawer, xDim, yDim vg.Length, format string) error {
+	xDim, yDim = plotDims(xDim, yDim)
+	return writeDrawing(w, xDim, yDim, format, d.Draw)
+}
+
+// Bytes returns the plot of the given size encoded in the given format, as
+// Write does.
+func Bytes(d Drawer, xDim, yDim vg.Length, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Write(&buf, d, xDim, yDim, format); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Image returns the image of the plot of the given size at 96 dpi. The
+// default size is used when a dimension is 0.
+func Image(d Drawer, xDim, yDim vg.Length) image.Image {
+	xDim, yDim = plotDims(xDim, yDim)
+	return renderImage(xDim, yDim, d.Draw)
+}
+
+// WriteLinePlot writes the line plot to w in the given format.
+func WriteLinePlot(w io.Writer, format string, lines Lines) error {
+	p, err := NewLinePlot(lines)
+	if err != nil {
+		return err
+	}
+	err = Write(w, p, lines.XDim, lines.YDim, format)
+	if err != nil {
+		return fmt.Errorf("line plot: %w", err)
+	}
+	return nil
+}
+
+// WriteSpikePlot writes the spike plot to w in the given format.
+func WriteSpikePlot(w io.Writer, format string, spikeLines SpikeLines) error {
+	err := spikeLines.checkUnits()
+	if err == nil {
+		err = Write(w, NewSpikePlot(spikeLines), spikeLines.XDim, spikeLines.YDim, format)
+	}
+	if err != nil {
+		return fmt.Errorf("spike plot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package plots
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/plot/vg"
+)
+
+func TestWritePlots(t *testing.T) {
+	lines := Lines{
+		Title: "Written",
+		Lines: []Line{{Points: XYs([]float64{1, 3, 2})}},
+		XDim:  4 * vg.Inch,
+		YDim:  3 * vg.Inch,
+	}
+	var buf bytes.Buffer
+	if err := WriteLinePlot(&buf, "svg", lines); err != nil {
+		t.Fatalf("failed writing line plot: %s", err)
+	}
+	if !strings.Contains(buf.String(), "<svg") {
+		t.Errorf("expected svg output")
+	}
+	buf.Reset()
+	spikes := SpikeLines{Lines: []SpikeLine{{Label: "1", Spikes: []float64{0.1, 0.5}}}}
+	if err := WriteSpikePlot(&buf, "png", spikes); err != nil {
+		t.Fatalf("failed writing spike plot: %s", err)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Errorf("failed decoding png: %s", err)
+	}
+	if err := WriteSpikePlot(&buf, "bmp", spikes); err == nil {
+		t.Errorf("expected error with unsupported format")
+	}
+	os.MkdirAll("tests", 0766)
+	if err := MakeSpikePlot(spikes, "tests/unsupported.bmp"); err == nil {
+		t.Errorf("expected error with unsupported format")
+	}
+	if _, err := os.Stat("tests/unsupported.bmp"); err == nil {
+		t.Errorf("expected no file with unsupported format")
+	}
+
+	p, err := NewLinePlot(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Y.Min = 0
+	img := Image(p, lines.XDim, lines.YDim)
+	if b := img.Bounds(); b.Dx() != 384 || b.Dy() != 288 {
+		t.Errorf("got image size %dx%d, expected 384x288", b.Dx(), b.Dy())
+	}
+	data, err := Bytes(NewSpikePlot(spikes), 0, 0, "pdf")
+	if err != nil || !bytes.HasPrefix(data, []byte("%PDF")) {
+		t.Errorf("expected pdf output, got error %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package plots
+
+import (
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// rightAxis is a plotter drawing the lines of the right Y axis, and the axis
+// at the right of the data area. The lines are drawn with the X axis of the
+// plot and the Y axis of the right plot.
+type rightAxis struct {
+	right    *plot.Plot
+	plotters []plot.Plotter
+}
+
+// newRightAxis returns the right axis with the given scale and label.
+func newRightAxis(scale Scale, label string) *rightAxis {
+	right := plot.New()
+	scale.apply(&right.Y)
+	right.Y.Label.Text = label
+	right.Y.Tick.Label.XAlign = draw.XLeft
+	return &rightAxis{right: right}
+}
+
+// Add adds the plotters of a line of the right axis.
+func (a *rightAxis) Add(ps ...plot.Plotter) {
+	a.right.Add(ps...)
+	a.plotters = append(a.plotters, ps...)
+}
+
+// setRange sets the range of the axis as setRange does, and widens an empty
+// range as gonum does for the plot axes.
+func (a *rightAxis) setRange(limit *Limit, scale Scale, padding float64) {
+	y := &a.right.Y
+	setRange(y, limit, scale, false, padding)
+	if math.IsInf(y.Min, 0) || math.IsInf(y.Max, 0) {
+		y.Min, y.Max = 0, 1
+	}
+	if y.Min == y.Max {
+		y.Min, y.Max = y.Min-1, y.Max+1
+	}
+}
+
+// DataRange returns the X range of the lines, and an empty Y range so that
+// the Y range of the plot is not changed.
+func (a *rightAxis) DataRange() (xmin, xmax, ymin, ymax float64) {
+	return a.right.X.Min, a.right.X.Max, math.Inf(1), math.Inf(-1)
+}
+
+// Plot draws the lines and the axis.
+func (a *rightAxis) Plot(c draw.Canvas, p *plot.Plot) {
+	right := *a.right
+	right.X = p.X
+	for _, pl := range a.plotters {
+		pl.Plot(c, &right)
+	}
+	a.drawAxis(c)
+}
+
+// ticks returns the ticks of the axis, and the width of the widest
+// major tick label.
+func (a *rightAxis) ticks() ([]plot.Tick, vg.Length) {
+	y := &a.right.Y
+	ticks := y.Tick.Marker.Ticks(y.Min, y.Max)
+	var width vg.Length
+	for _, t := range ticks {
+		if t.Label != "" {
+			width = max(width, y.Tick.Label.Width(t.Label))
+		}
+	}
+	return ticks, width
+}
+
+// width returns the width of the axis at the right of the data area.
+func (a *rightAxis) width() vg.Length {
+	y := &a.right.Y
+	_, labelWidth := a.ticks()
+	w := y.Padding + y.Tick.Length
+	if labelWidth > 0 {
+		w += y.Tick.Label.Width(" ") + labelWidth
+	}
+	if y.Label.Text != "" {
+		w += y.Label.Padding + y.Label.TextStyle.Height(y.Label.Text)
+	}
+	return w
+}
+
+// drawAxis draws the axis line, ticks and labels at the right of the data
+// area c, mirroring the left axis.
+func (a *rightAxis) drawAxis(c draw.Canvas) {
+	y := &a.right.Y
+	x := c.Max.X + y.Padding
+	c.StrokeLine2(y.LineStyle, x, c.Min.Y, x, c.Max.Y)
+	ticks, labelWidth := a.ticks()
+	for _, t := range ticks {
+		yt := c.Y(y.Norm(t.Value))
+		if !c.ContainsY(yt) {
+			continue
+		}
+		length := y.Tick.Length
+		if t.IsMinor() {
+			length /= 2
+		}
+		c.StrokeLine2(y.Tick.LineStyle, x, yt, x+length, yt)
+	}
+	x += y.Tick.Length
+	if labelWidth > 0 {
+		x += y.Tick.Label.Width(" ")
+		descent := y.Tick.Label.FontExtents().Descent
+		for _, t := range ticks {
+			yt := c.Y(y.Norm(t.Value))
+			if !c.ContainsY(yt) || t.IsMinor() {
+				continue
+			}
+			c.FillText(y.Tick.Label, vg.Point{X: x, Y: yt + descent}, t.Label)
+		}
+		x += labelWidth
+	}
+	if y.Label.Text != "" {
+		sty := y.Label.TextStyle
+		sty.Rotation += math.Pi / 2
+		x += y.Label.Padding + y.Label.TextStyle.Height(y.Label.Text)
+		descent := y.Label.TextStyle.FontExtents().Descent
+		c.FillText(sty, vg.Point{X: x - descent, Y: c.Center().Y}, y.Label.Text)
+	}
+}
+
+// GlyphBoxes returns the boxes reserving the space of the axis at the right
+// of the data area, and of half the tick labels above and below it.
+func (a *rightAxis) GlyphBoxes(p *plot.Plot) []plot.GlyphBox {
+	w := a.width()
+	h := a.right.Y.Tick.Label.Height("0")
+	return []plot.GlyphBox{
+		{X: 1, Y: 0, Rectangle: vg.Rectangle{Min: vg.Point{Y: -h / 2}, Max: vg.Point{X: w, Y: h / 2}}},
+		{X: 1, Y: 1, Rectangle: vg.Rectangle{Min: vg.Point{Y: -h / 2}, Max: vg.Point{X: w, Y: h / 2}}},
+	}
+}
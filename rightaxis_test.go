@@ -0,0 +1,60 @@
+package plots
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestRightAxisLinePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var accuracy, loss plotter.XYs
+	for i := 1; i <= 50; i++ {
+		x := float64(i)
+		accuracy = append(accuracy, plotter.XY{X: x, Y: 0.95 - 0.6*math.Exp(-x/10)})
+		loss = append(loss, plotter.XY{X: x, Y: 3 * math.Exp(-x/8)})
+	}
+	lines := Lines{
+		Title:      "accuracy and loss",
+		XLabel:     "Epoch",
+		YLabel:     "Accuracy",
+		RightLabel: "Loss",
+		RightScale: Scale{Type: Log10Scale},
+		Lines: []Line{
+			{Label: "accuracy", Points: accuracy, Color: DarkColors.Id(1)},
+			{Label: "loss", Points: loss, Color: DarkColors.Id(2), RightAxis: true},
+		},
+	}
+	if err := MakeLinePlot(lines, "tests/rightAxisLinePlot.png", "tests/rightAxisLinePlot.svg"); err != nil {
+		t.Fatal(err)
+	}
+	p, right, err := newLinePlot(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Y.Max > 1 {
+		t.Errorf("got left Y max %g, want at most 1", p.Y.Max)
+	}
+	if p.X.Min != 1 || p.X.Max != 50 {
+		t.Errorf("got X range [%g,%g], want [1,50]", p.X.Min, p.X.Max)
+	}
+	if y := right.right.Y; y.Min != loss[len(loss)-1].Y || y.Max != loss[0].Y {
+		t.Errorf("got right Y range [%g,%g], want [%g,%g]", y.Min, y.Max, loss[len(loss)-1].Y, loss[0].Y)
+	}
+
+	// all lines on the right axis with a log left axis
+	lines.YScale = Scale{Type: Log10Scale}
+	lines.Lines = lines.Lines[1:]
+	if err := MakeLinePlot(lines, "tests/rightOnlyLinePlot.png"); err != nil {
+		t.Fatal(err)
+	}
+	p, right, err = newLinePlot(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Y.Min != right.right.Y.Min || p.Y.Max != right.right.Y.Max {
+		t.Errorf("got left Y range [%g,%g], want the right range [%g,%g]", p.Y.Min, p.Y.Max, right.right.Y.Min, right.right.Y.Max)
+	}
+}
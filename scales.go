@@ -0,0 +1,192 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"gonum.org/v1/plot"
+)
+
+// ScaleType is the type of an axis scale.
+type ScaleType int
+
+const (
+	LinearScale ScaleType = iota // Linear scale.
+	Log10Scale                   // Logarithmic scale with ticks at the powers of 10.
+	LnScale                      // Logarithmic scale with ticks at the powers of e.
+	SymLogScale                  // Symmetric logarithmic scale, linear around 0.
+)
+
+// Scale is an axis scale.
+type Scale struct {
+	Type      ScaleType // Scale type (default = LinearScale).
+	Threshold float64   // Half width of the linear range around 0 of SymLogScale (default = 1).
+	Reversed  bool      // Values decrease along the axis.
+}
+
+// isLog returns true if the scale requires positive values.
+func (s Scale) isLog() bool {
+	return s.Type == Log10Scale || s.Type == LnScale
+}
+
+// threshold returns the SymLogScale threshold with its default value.
+func (s Scale) threshold() float64 {
+	if s.Threshold <= 0 {
+		return 1
+	}
+	return s.Threshold
+}
+
+// apply sets the normalizer and the ticker of the axis.
+func (s Scale) apply(a *plot.Axis) {
+	switch s.Type {
+	case Log10Scale:
+		a.Scale, a.Tick.Marker = plot.LogScale{}, logTicks{base: 10}
+	case LnScale:
+		a.Scale, a.Tick.Marker = plot.LogScale{}, logTicks{base: math.E}
+	case SymLogScale:
+		a.Scale, a.Tick.Marker = symLogScale{s.threshold()}, symLogTicks{s.threshold()}
+	default:
+		a.Scale, a.Tick.Marker = plot.LinearScale{}, plot.DefaultTicks{}
+	}
+	if s.Reversed {
+		a.Scale = plot.InvertedScale{Normalizer: a.Scale}
+	}
+}
+
+// check returns an error if v is out of the domain of the scale of the named
+// axis.
+func (s Scale) check(axis string, v float64) error {
+	if s.isLog() && !(v > 0) {
+		return fmt.Errorf("non-positive %s value %g with a log scale", axis, v)
+	}
+	return nil
+}
+
+// checkScales returns an error if the line has points, points with their
+// uncertainty, a baseline or FillTo points out of the domain of the axis
+// scales.
+func checkScales(line Line, xScale, yScale Scale) error {
+	if !xScale.isLog() && !yScale.isLog() {
+		return nil
+	}
+	if line.Mode == StemMode || line.Mode == AreaMode {
+		if err := yScale.check("Y baseline", line.Baseline); err != nil {
+			return err
+		}
+	}
+	if line.Mode == FillBetweenMode && line.FillTo != nil {
+		for i := 0; i < line.FillTo.Len(); i++ {
+			x, y := line.FillTo.XY(i)
+			if err := xScale.check("X FillTo", x); err != nil {
+				return err
+			}
+			if err := yScale.check("Y FillTo", y); err != nil {
+				return err
+			}
+		}
+	}
+	for i := 0; i < line.Points.Len(); i++ {
+		x, y := line.Points.XY(i)
+		if i < len(line.XErrors) {
+			x -= math.Abs(line.XErrors[i])
+		}
+		if i < len(line.YErrors) {
+			y -= math.Abs(line.YErrors[i])
+		}
+		if err := xScale.check("X", x); err != nil {
+			return err
+		}
+		if err := yScale.check("Y", y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logTicks are the ticks of a logarithmic axis with major ticks at the
+// powers of base. The default ticks are used when the range contains less
+// than two major ticks.
+type logTicks struct {
+	base float64
+}
+
+// Ticks returns the ticks in [min,max].
+func (t logTicks) Ticks(min, max float64) []plot.Tick {
+	var ticks []plot.Tick
+	if t.base == 10 {
+		ticks = plot.LogTicks{Prec: -1}.Ticks(min, max)
+	} else {
+		logBase := math.Log(t.base)
+		for k := math.Ceil(math.Log(min) / logBase); k <= math.Floor(math.Log(max)/logBase); k++ {
+			ticks = append(ticks, plot.Tick{Value: math.Pow(t.base, k), Label: "e^" + strconv.Itoa(int(k))})
+		}
+	}
+	var labeled int
+	for _, tick := range ticks {
+		if tick.Label != "" && tick.Value >= min && tick.Value <= max {
+			labeled++
+		}
+	}
+	if labeled < 2 {
+		return plot.DefaultTicks{}.Ticks(min, max)
+	}
+	return ticks
+}
+
+// symLogScale is the normalizer of the symmetric logarithmic scale, which is
+// sign(x)*log10(1+|x|/threshold).
+type symLogScale struct {
+	threshold float64
+}
+
+// transform returns the scaled value of x.
+func (s symLogScale) transform(x float64) float64 {
+	return math.Copysign(math.Log10(1+math.Abs(x)/s.threshold), x)
+}
+
+// Normalize returns the fractional position of x in [min,max].
+func (s symLogScale) Normalize(min, max, x float64) float64 {
+	tMin := s.transform(min)
+	return (s.transform(x) - tMin) / (s.transform(max) - tMin)
+}
+
+// symLogTicks are the ticks of the symmetric logarithmic scale, with major
+// ticks at 0 and at the signed powers of 10 not smaller than the threshold.
+// The default ticks are used when the range contains less than two major
+// ticks.
+type symLogTicks struct {
+	threshold float64
+}
+
+// Ticks returns the ticks in [min,max].
+func (t symLogTicks) Ticks(min, max float64) []plot.Tick {
+	var ticks []plot.Tick
+	var labeled int
+	add := func(v float64, label bool) {
+		if v < min || v > max {
+			return
+		}
+		tick := plot.Tick{Value: v}
+		if label {
+			tick.Label = strconv.FormatFloat(v, 'g', -1, 64)
+			labeled++
+		}
+		ticks = append(ticks, tick)
+	}
+	add(0, true)
+	kMin := math.Ceil(math.Log10(t.threshold))
+	kMax := math.Ceil(math.Log10(math.Max(math.Abs(min), math.Abs(max))))
+	for k := kMin; k <= kMax; k++ {
+		p := math.Pow(10, k)
+		for m := 1; m < 10; m++ {
+			add(float64(m)*p, m == 1)
+			add(-float64(m)*p, m == 1)
+		}
+	}
+	if labeled < 2 {
+		return plot.DefaultTicks{}.Ticks(min, max)
+	}
+	return ticks
+}
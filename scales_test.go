@@ -0,0 +1,99 @@
+package plots
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestScaleLinePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var loss, rate, signal plotter.XYs
+	for i := 1; i <= 100; i++ {
+		x := float64(i)
+		loss = append(loss, plotter.XY{X: x, Y: 10 * math.Exp(-x/15)})
+		rate = append(rate, plotter.XY{X: x, Y: 0.5 * math.Sqrt(x)})
+		signal = append(signal, plotter.XY{X: x, Y: 50 * math.Sin(x/10) * math.Exp(-x/30)})
+	}
+	lines := Lines{
+		Title:  "log scales",
+		XLabel: "Epoch",
+		YLabel: "Loss",
+		XScale: Scale{Type: Log10Scale},
+		YScale: Scale{Type: Log10Scale},
+		Lines: []Line{
+			{Label: "loss", Points: loss, Color: DarkColors.Id(1)},
+			{Label: "rate", Points: rate, Color: DarkColors.Id(2)},
+		},
+	}
+	if err := MakeLinePlot(lines, "tests/log10LinePlot.png"); err != nil {
+		t.Fatal(err)
+	}
+	lines.Title = "natural log scale"
+	lines.XScale = Scale{}
+	lines.YScale = Scale{Type: LnScale}
+	if err := MakeLinePlot(lines, "tests/lnLinePlot.png"); err != nil {
+		t.Fatal(err)
+	}
+	lines = Lines{
+		Title:  "reversed symlog scale",
+		XScale: Scale{Reversed: true},
+		YScale: Scale{Type: SymLogScale, Threshold: 1},
+		Lines: []Line{
+			{Label: "signal", Points: signal, Color: DarkColors.Id(3)},
+		},
+	}
+	if err := MakeLinePlot(lines, "tests/symlogLinePlot.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	lines.YScale = Scale{Type: Log10Scale}
+	_, err := NewLinePlot(lines)
+	if err == nil || !strings.Contains(err.Error(), "'signal'") {
+		t.Errorf("got error %v, want an error naming the line", err)
+	}
+}
+
+func TestSymLogScale(t *testing.T) {
+	s := symLogScale{threshold: 1}
+	if got := s.Normalize(-100, 100, 0); math.Abs(got-0.5) > 1e-12 {
+		t.Errorf("got %g, want 0.5", got)
+	}
+	if got := s.Normalize(-100, 100, 100); got != 1 {
+		t.Errorf("got %g, want 1", got)
+	}
+	ticks := symLogTicks{threshold: 1}.Ticks(-100, 100)
+	var labels []string
+	for _, tick := range ticks {
+		if tick.Label != "" {
+			labels = append(labels, tick.Label)
+		}
+	}
+	if got := strings.Join(labels, " "); got != "0 1 -1 10 -10 100 -100" {
+		t.Errorf("got labels %q", got)
+	}
+}
+
+func TestEmptyLogScale(t *testing.T) {
+	var buf bytes.Buffer
+	lines := Lines{YScale: Scale{Type: Log10Scale}}
+	if err := WriteLinePlot(&buf, "png", lines); err != nil {
+		t.Fatal(err)
+	}
+	lines.XScale = Scale{Type: LnScale}
+	lines.Lines = []Line{{Label: "empty", Points: plotter.XYs{}}}
+	p, err := NewLinePlot(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.X.Min != 1 || p.X.Max != 10 || p.Y.Min != 1 || p.Y.Max != 10 {
+		t.Errorf("got ranges [%g,%g] [%g,%g], want [1,10]", p.X.Min, p.X.Max, p.Y.Min, p.Y.Max)
+	}
+	if err := WriteLinePlot(&buf, "png", lines); err != nil {
+		t.Fatal(err)
+	}
+}
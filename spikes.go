@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"slices"
 	"sort"
 
 	"gonum.org/v1/plot"
@@ -13,11 +14,22 @@ import (
 
 // SpikeLines is a plot of spikes lines drawn from top to bottom.
 type SpikeLines struct {
-	Title  string      // Title
-	Lines  []SpikeLine // Spike lines.
-	XLimit *Limit      // Spike time range limit.
-	XDim   vg.Length   // X dimension of saved plot, use default if 0.
-	YDim   vg.Length   // Y dimension of saved plot, use default if 0.
+	Title       string          // Title
+	Lines       []SpikeLine     // Spike lines.
+	XLimit      *Limit          // Spike time range limit.
+	Unit        TimeUnit        // Spike time unit (default = Seconds).
+	DisplayUnit TimeUnit        // Time unit of the plot with converted spike times, if both durations are known (default = Unit).
+	Relative    bool            // Show times relative to XLimit.Min, ignored if XLimit is nil.
+	XLabel      string          // X axis label (default = "Time (<unit>)").
+	Sync        *Synchrony      // Synchronous events to highlight, none if nil.
+	Epochs      Epochs          // Time intervals drawn as bands behind the spikes.
+	Dense       *DenseRaster    // Dense raster drawing for many lines, none if nil.
+	Order       Order           // Line ordering, slice order if nil.
+	Groups      []string        // Population group names in drawing order.
+	GroupColors ColorTable      // Default spike color of each group, none if nil.
+	Categories  []SpikeCategory // Spike categories indexed by SpikeLine.Categories.
+	XDim        vg.Length       // X dimension of saved plot, use default if 0.
+	YDim        vg.Length       // Y dimension of saved plot, use default if 0.
 
 }
 
@@ -28,10 +40,94 @@ type Limit struct {
 // SpikeLine is a labeled sequence of spike event time values.
 // Requires that spike event times are sorted in increasing order.
 type SpikeLine struct {
-	Label    string            // Spike sequence label.
-	Spikes   []float64         // Spike event time values.
-	ZIndex   int               // Drawing order in increasing value order.
-	Property SpikeLineProperty // Spike line property (use default if nil).
+	Label      string            // Spike sequence label.
+	Spikes     []float64         // Spike event time values.
+	ZIndex     int               // Drawing order in increasing value order.
+	Property   SpikeLineProperty // Spike line property (use default if nil).
+	Categories []int             // Category index of each spike, none if nil or negative.
+	Group      string            // Population group name, none if empty.
+}
+
+// Category returns the category index of spike i, or -1 if it has none.
+func (l *SpikeLine) Category(i int) int {
+	if i >= len(l.Categories) || l.Categories[i] < 0 {
+		return -1
+	}
+	return l.Categories[i]
+}
+
+// SpikeCategory is the graphical property of a category of spikes. It
+// overrides the spike properties of the line.
+type SpikeCategory struct {
+	Label  string      // Category label for the legend, not in legend if empty.
+	Color  color.Color // Spike color (default = line spike color).
+	Width  vg.Length   // Spike stroke width (default = line spike width).
+	Height float64     // Spike height relative to line spacing (default = 0.5).
+}
+
+// Thumbnail draws a spike with the category property for the legend.
+func (c SpikeCategory) Thumbnail(canvas *draw.Canvas) {
+	lineStyle := draw.LineStyle{
+		Color: color.RGBA{0, 0, 0, 255},
+		Width: vg.Points(1),
+	}
+	if c.Color != nil {
+		lineStyle.Color = c.Color
+	}
+	if c.Width != 0 {
+		lineStyle.Width = c.Width
+	}
+	x := canvas.Center().X
+	canvas.StrokeLine2(lineStyle, x, canvas.Min.Y, x, canvas.Max.Y)
+}
+
+// lineProperty returns the spike property of the line with the default
+// values, and the group color as default spike color.
+func (s SpikeLines) lineProperty(l *SpikeLine) SpikeLineProperty {
+	spikeProperty := DefaultSpikeProperty()
+	if l.Property.Extend != 0 {
+		spikeProperty.Extend = l.Property.Extend
+		if l.Property.ExtendWidth != 0 {
+			spikeProperty.ExtendWidth = l.Property.ExtendWidth
+		}
+		if l.Property.ExtendColor != nil {
+			spikeProperty.ExtendColor = l.Property.ExtendColor
+		}
+	}
+	if l.Property.Width != 0 {
+		spikeProperty.Width = l.Property.Width
+	}
+	if l.Property.Color != nil {
+		spikeProperty.Color = l.Property.Color
+	} else if c := s.groupColor(l.Group); c != nil {
+		spikeProperty.Color = c
+	}
+	if l.Property.LWidth != 0 {
+		spikeProperty.LWidth = l.Property.LWidth
+	}
+	if l.Property.LColor != nil {
+		spikeProperty.LColor = l.Property.LColor
+	}
+	return spikeProperty
+}
+
+// category returns the spike category i with the default color and width
+// of the spikes of the first line having spikes in the category.
+func (s SpikeLines) category(i int) SpikeCategory {
+	c := s.Categories[i]
+	for k := range s.Lines {
+		if l := &s.Lines[k]; slices.Contains(l.Categories, i) {
+			spikeProperty := s.lineProperty(l)
+			if c.Color == nil {
+				c.Color = spikeProperty.Color
+			}
+			if c.Width == 0 {
+				c.Width = spikeProperty.Width
+			}
+			break
+		}
+	}
+	return c
 }
 
 // SpikeLineProperty is the the graphical property of spikes.
@@ -60,6 +156,13 @@ func DefaultSpikeProperty() SpikeLineProperty {
 
 // Plot draws the spike lines.
 func (s SpikeLines) Plot(canvas draw.Canvas, plt *plot.Plot) {
+	// draw group separators behind the lines
+	s.drawSeparators(canvas, plt)
+
+	if s.Dense != nil {
+		s.plotDense(canvas, plt)
+		return
+	}
 	trX, trY := plt.Transforms(&canvas)
 
 	// determine drawing order
@@ -71,6 +174,16 @@ func (s SpikeLines) Plot(canvas draw.Canvas, plt *plot.Plot) {
 		return s.Lines[drawingOrder[i]].ZIndex < s.Lines[drawingOrder[j]].ZIndex
 	})
 
+	// draw synchronous event bars behind the lines
+	var coincident [][]bool
+	if s.Sync != nil {
+		events := DetectSync(s.Lines, s.Sync.Window, s.Sync.MinLines)
+		s.Sync.draw(canvas, plt, events, len(s.Lines))
+		if s.Sync.SpikeColor != nil {
+			coincident = coincidentSpikes(s.Lines, events)
+		}
+	}
+
 	// horizontal line coordinates
 	xMinPx := trX(plt.X.Min)
 	xMaxPx := trX(plt.X.Max)
@@ -82,29 +195,7 @@ func (s SpikeLines) Plot(canvas draw.Canvas, plt *plot.Plot) {
 	for _, i := range drawingOrder {
 		l := &s.Lines[i]
 
-		// get property by using default values
-		spikeProperty := DefaultSpikeProperty()
-		if l.Property.Extend != 0 {
-			spikeProperty.Extend = l.Property.Extend
-			if l.Property.ExtendWidth != 0 {
-				spikeProperty.ExtendWidth = l.Property.ExtendWidth
-			}
-			if l.Property.ExtendColor != nil {
-				spikeProperty.ExtendColor = l.Property.ExtendColor
-			}
-		}
-		if l.Property.Width != 0 {
-			spikeProperty.Width = l.Property.Width
-		}
-		if l.Property.Color != nil {
-			spikeProperty.Color = l.Property.Color
-		}
-		if l.Property.LWidth != 0 {
-			spikeProperty.LWidth = l.Property.LWidth
-		}
-		if l.Property.LColor != nil {
-			spikeProperty.LColor = l.Property.LColor
-		}
+		spikeProperty := s.lineProperty(l)
 
 		// find spikes to draw
 		beg := sort.SearchFloat64s(l.Spikes, plt.X.Min)
@@ -139,7 +230,10 @@ func (s SpikeLines) Plot(canvas draw.Canvas, plt *plot.Plot) {
 			Color: spikeProperty.Color,
 			Width: spikeProperty.Width,
 		})
-		for _, v := range spikes {
+		for k, v := range spikes {
+			if l.Category(beg+k) >= 0 || (coincident != nil && coincident[i][beg+k]) {
+				continue
+			}
 			var path vg.Path
 			xPixel := trX(v)
 			path.Move(vg.Point{X: xPixel, Y: yMinPx})
@@ -147,6 +241,43 @@ func (s SpikeLines) Plot(canvas draw.Canvas, plt *plot.Plot) {
 			canvas.Stroke(path)
 		}
 
+		// draw categorized and coincident spikes if any
+		if l.Categories != nil || coincident != nil {
+			for k, v := range spikes {
+				c := l.Category(beg + k)
+				isCoincident := coincident != nil && coincident[i][beg+k]
+				if c < 0 && !isCoincident {
+					continue
+				}
+				lineStyle := draw.LineStyle{
+					Color: spikeProperty.Color,
+					Width: spikeProperty.Width,
+				}
+				height := dy / 2
+				if c >= 0 && c < len(s.Categories) {
+					category := &s.Categories[c]
+					if category.Color != nil {
+						lineStyle.Color = category.Color
+					}
+					if category.Width != 0 {
+						lineStyle.Width = category.Width
+					}
+					if category.Height != 0 {
+						height = dy * category.Height
+					}
+				}
+				if isCoincident {
+					lineStyle.Color = s.Sync.SpikeColor
+				}
+				canvas.SetLineStyle(lineStyle)
+				var path vg.Path
+				xPixel := trX(v)
+				path.Move(vg.Point{X: xPixel, Y: yMinPx})
+				path.Line(vg.Point{X: xPixel, Y: trY(yMin + height)})
+				canvas.Stroke(path)
+			}
+		}
+
 		// draw horizontal line
 		canvas.SetLineStyle(draw.LineStyle{
 			Color: spikeProperty.LColor,
@@ -159,8 +290,12 @@ func (s SpikeLines) Plot(canvas draw.Canvas, plt *plot.Plot) {
 	}
 }
 
-// Ticks generates the Y axis ticks with the line labels.
+// Ticks generates the Y axis ticks with the line labels. In dense raster mode,
+// only some line labels are shown.
 func (s SpikeLines) Ticks(min, max float64) []plot.Tick {
+	if s.Dense != nil {
+		return s.denseTicks(min, max)
+	}
 	dy := (max - min) / float64(len(s.Lines))
 	ticks := make([]plot.Tick, 0, len(s.Lines))
 	for i := range s.Lines {
@@ -177,42 +312,74 @@ func MakeSpikePlot(spikeLines SpikeLines, fileNames ...string) error {
 	if len(fileNames) == 0 {
 		return nil
 	}
+	if err := spikeLines.checkUnits(); err != nil {
+		return fmt.Errorf("spike plot: %w", err)
+	}
 
-	p := plot.New()
-	p.Title.Text = spikeLines.Title
-	p.X.Label.Text = "Time (s)"
-	if spikeLines.XLimit == nil {
-		xMin, xMax := math.Inf(1), math.Inf(-1)
-		for i := range spikeLines.Lines {
-			for _, v := range spikeLines.Lines[i].Spikes {
-				if v < xMin {
-					xMin = v
-				}
-				if v > xMax {
-					xMax = v
-				}
-			}
+	p := NewSpikePlot(spikeLines)
+	xDim, yDim := plotDims(spikeLines.XDim, spikeLines.YDim)
+	for _, fileName := range fileNames {
+		err := saveDrawing(xDim, yDim, fileName, p.Draw)
+		if err != nil {
+			return fmt.Errorf("spike plot: %w", err)
 		}
-		p.X.Min = xMin
-		p.X.Max = xMax
-	} else {
-		p.X.Min = spikeLines.XLimit.Min
-		p.X.Max = spikeLines.XLimit.Max
 	}
+	return nil
+}
+
+// SpikePlot is the plot of spike lines. It draws the group brackets and
+// names on the left of the plot when lines belong to groups. The embedded
+// plot may be customized before drawing or saving it.
+type SpikePlot struct {
+	*plot.Plot
+	lines SpikeLines // Sorted spike lines.
+}
+
+// NewSpikePlot returns the plot of the spike lines converted to the display
+// unit and sorted by their order and groups. The display unit is ignored
+// when the duration of the spike time unit or of the display unit is unknown.
+func NewSpikePlot(spikeLines SpikeLines) *SpikePlot {
+	spikeLines = spikeLines.converted().Sorted()
+	p := plot.New()
+	p.Title.Text = spikeLines.Title
+	p.X.Label.Text = spikeLines.xLabel()
+	p.X.Tick.Marker = timeTicks{unit: spikeLines.Unit}
+	p.X.Min, p.X.Max = spikeLines.xRange()
 	p.Y.Tick.Marker = spikeLines
+	if len(spikeLines.Epochs) != 0 {
+		p.Add(spikeLines.Epochs)
+	}
 	p.Add(spikeLines)
-	xDim, yDim := spikeLines.XDim, spikeLines.YDim
-	if xDim == 0 {
-		xDim = 15 * vg.Centimeter
+	for i, c := range spikeLines.Categories {
+		if c.Label != "" {
+			p.Legend.Add(c.Label, spikeLines.category(i))
+		}
 	}
-	if yDim == 0 {
-		yDim = 15 * vg.Centimeter
+	p.Legend.Top = true
+	return &SpikePlot{Plot: p, lines: spikeLines}
+}
+
+// xRange returns the XLimit values, or the min and max spike time values
+// when XLimit is nil.
+func (s SpikeLines) xRange() (xMin, xMax float64) {
+	if s.XLimit != nil {
+		return s.XLimit.Min, s.XLimit.Max
 	}
-	for _, fileName := range fileNames {
-		err := p.Save(xDim, yDim, fileName)
-		if err != nil {
-			return fmt.Errorf("spike plot: %w", err)
+	return spikesRange(s.Lines)
+}
+
+// spikesRange returns the min and max spike time values of the spike lines.
+func spikesRange(lines []SpikeLine) (xMin, xMax float64) {
+	xMin, xMax = math.Inf(1), math.Inf(-1)
+	for i := range lines {
+		for _, v := range lines[i].Spikes {
+			if v < xMin {
+				xMin = v
+			}
+			if v > xMax {
+				xMax = v
+			}
 		}
 	}
-	return nil
+	return xMin, xMax
 }
@@ -1,6 +1,7 @@
 package plots
 
 import (
+	"bytes"
 	"fmt"
 	"image/color"
 	"math"
@@ -187,3 +188,214 @@ func TestManySpikeLines(t *testing.T) {
 		t.Fatalf("failed saving image: %s", err)
 	}
 }
+
+func TestDetectSync(t *testing.T) {
+	lines := []SpikeLine{
+		{Spikes: []float64{1, 2, 3}},
+		{Spikes: []float64{1.001, 2.5, 3.002}},
+		{Spikes: []float64{0.5, 2.002, 3.001}},
+	}
+	events := DetectSync(lines, 0.005, 2)
+	if len(events) != 3 {
+		t.Fatalf("got %d events, expected 3", len(events))
+	}
+	expLines := [][]int{{0, 1}, {0, 2}, {0, 1, 2}}
+	for i, e := range events {
+		if fmt.Sprint(e.Lines) != fmt.Sprint(expLines[i]) {
+			t.Fatalf("got event %d lines %v, expected %v", i, e.Lines, expLines[i])
+		}
+	}
+	if events := DetectSync(lines, 0.005, 3); len(events) != 1 || events[0].Time != 3.001 {
+		t.Fatalf("got events %v, expected one event at time 3.001", events)
+	}
+}
+
+func TestSyncSpikePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	duration := 4.
+	commonSpikes := GeneratePoissonDistributedSpikes(duration, 3, 0.02)
+	var spikes SpikeLines
+	for i := 0; i < 15; i++ {
+		spikes.Lines = append(spikes.Lines, SpikeLine{
+			Label:  fmt.Sprintf("%d", i+1),
+			Spikes: GeneratePoissonDistributedSpikes(duration, 8, 0.02),
+		})
+		if rng.Float64() < .5 {
+			spikes.Lines[i].Spikes = AddPoissonNoise(commonSpikes, duration, 5, 0.02)
+		}
+	}
+	spikes.Title = "Synchronous events"
+	spikes.Sync = &Synchrony{
+		Window:     0.005,
+		MinLines:   3,
+		Color:      color.RGBA{255, 150, 150, 255},
+		Width:      vg.Points(3),
+		SpikeColor: color.RGBA{0, 0, 255, 255},
+	}
+	err := MakeSpikePlot(spikes, "tests/syncSpikePlot.png", "tests/syncSpikePlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+}
+
+func TestCategorySpikePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	duration := 4.
+	var spikes SpikeLines
+	for i := 0; i < 10; i++ {
+		line := SpikeLine{
+			Label:    fmt.Sprintf("%d", i+1),
+			Spikes:   GeneratePoissonDistributedSpikes(duration, 8, 0.02),
+			Property: SpikeLineProperty{Color: DarkColors.Id(3)},
+		}
+		line.Categories = make([]int, len(line.Spikes))
+		for j, v := range line.Spikes {
+			switch {
+			case v > 1 && v < 2:
+				line.Categories[j] = 1
+			case rng.Float64() < 0.1:
+				line.Categories[j] = 2
+			}
+		}
+		spikes.Lines = append(spikes.Lines, line)
+	}
+	spikes.Title = "Spike categories"
+	spikes.Categories = []SpikeCategory{
+		{Label: "spontaneous"},
+		{Label: "input", Color: DarkColors.Id(2), Width: vg.Points(2)},
+		{Label: "potentiated", Color: DarkColors.Id(1), Height: 0.8},
+	}
+	err := MakeSpikePlot(spikes, "tests/categorySpikePlot.png", "tests/categorySpikePlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	if c := spikes.category(0); c.Color != DarkColors.Id(3) || c.Width != vg.Points(1) {
+		t.Errorf("got legend category color %v and width %v, want the line spike color and width", c.Color, c.Width)
+	}
+	if c := spikes.category(1); c.Color != DarkColors.Id(2) || c.Width != vg.Points(2) {
+		t.Errorf("got legend category color %v and width %v, want the category color and width", c.Color, c.Width)
+	}
+}
+
+func TestDenseSpikePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	duration := 2.
+	var spikes SpikeLines
+	for i := 0; i < 2000; i++ {
+		spikes.Lines = append(spikes.Lines, SpikeLine{
+			Label:  fmt.Sprintf("%d", i),
+			Spikes: GeneratePoissonDistributedSpikes(duration, 20, 0.002),
+		})
+	}
+	spikes.Title = "Dense raster"
+	spikes.Dense = &DenseRaster{Dots: true, Size: vg.Points(0.3), Threshold: 10000}
+	err := MakeSpikePlot(spikes, "tests/denseSpikePlot.png", "tests/denseSpikePlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	spikes.Lines = spikes.Lines[:200]
+	spikes.Dense = &DenseRaster{Color: DarkColors.Id(2)}
+	err = MakeSpikePlot(spikes, "tests/denseVectorSpikePlot.png", "tests/denseVectorSpikePlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+}
+
+func TestSpikeLineOrder(t *testing.T) {
+	lines := []SpikeLine{
+		{Label: "10", Spikes: []float64{0.5}},
+		{Label: "2", Spikes: []float64{0.1, 0.2, 0.3}},
+		{Label: "3", Spikes: []float64{0.2, 0.4}},
+	}
+	if perm := ByLabel(lines); fmt.Sprint(perm) != "[1 2 0]" {
+		t.Errorf("got ByLabel %v, expected [1 2 0]", perm)
+	}
+	for _, labels := range [][]string{{"1a", "10", "9"}, {"9", "1a", "10"}, {"10", "9", "1a"}} {
+		mixed := make([]SpikeLine, len(labels))
+		for i, label := range labels {
+			mixed[i].Label = label
+		}
+		var sorted []string
+		for _, i := range ByLabel(mixed) {
+			sorted = append(sorted, labels[i])
+		}
+		if fmt.Sprint(sorted) != "[9 10 1a]" {
+			t.Errorf("got ByLabel %v for %v, expected [9 10 1a]", sorted, labels)
+		}
+	}
+	if perm := ByRate(lines); fmt.Sprint(perm) != "[1 2 0]" {
+		t.Errorf("got ByRate %v, expected [1 2 0]", perm)
+	}
+	if perm := ByLatency(0.15)(lines); fmt.Sprint(perm) != "[1 2 0]" {
+		t.Errorf("got ByLatency %v, expected [1 2 0]", perm)
+	}
+	if perm := ByLatency(0.45)(lines); fmt.Sprint(perm) != "[0 1 2]" {
+		t.Errorf("got ByLatency %v, expected [0 1 2]", perm)
+	}
+	s := SpikeLines{Lines: lines, Order: ByPermutation([]int{2, 0, 1}), Groups: []string{"I", "E"}}
+	s.Lines[0].Group = "E"
+	s.Lines[2].Group = "E"
+	s.Lines[1].Group = "I"
+	var labels []string
+	for _, l := range s.Sorted().Lines {
+		labels = append(labels, l.Label)
+	}
+	if fmt.Sprint(labels) != "[2 3 10]" {
+		t.Errorf("got sorted labels %v, expected [2 3 10]", labels)
+	}
+	for _, c := range []struct {
+		perm []int
+		want string
+	}{
+		{[]int{0, 2}, "[0 2 1]"},
+		{[]int{2, 2, 5, -1}, "[2 0 1]"},
+		{nil, "[0 1 2]"},
+	} {
+		if perm := ByPermutation(c.perm)(lines); fmt.Sprint(perm) != c.want {
+			t.Errorf("got ByPermutation(%v) %v, expected %s", c.perm, perm, c.want)
+		}
+	}
+}
+
+func TestGroupSpikePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	duration := 4.
+	var spikes SpikeLines
+	for i := 0; i < 30; i++ {
+		line := SpikeLine{
+			Label: fmt.Sprintf("%d", i+1),
+			Group: "excitatory",
+		}
+		switch {
+		case i%3 == 0:
+			line.Group = "inhibitory"
+			line.Spikes = GeneratePoissonDistributedSpikes(duration, 20, 0.02)
+		case i%2 == 0:
+			// bursting in the first half
+			line.Spikes = GeneratePoissonDistributedSpikes(duration/2, 10, 0.02)
+		default:
+			line.Spikes = GeneratePoissonDistributedSpikes(duration, 5, 0.02)
+		}
+		spikes.Lines = append(spikes.Lines, line)
+	}
+	spikes.Title = "Population groups"
+	spikes.Groups = []string{"excitatory", "inhibitory"}
+	spikes.GroupColors = DarkColors
+	spikes.Order = BySimilarity(0.5)
+	err := MakeSpikePlot(spikes, "tests/groupSpikePlot.png", "tests/groupSpikePlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	p := NewSpikePlot(spikes)
+	if err := p.Save(15*vg.Centimeter, 15*vg.Centimeter, "tests/groupSpikePlotSaved.svg"); err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	if b, err := os.ReadFile("tests/groupSpikePlotSaved.svg"); err != nil || !bytes.Contains(b, []byte("inhibitory")) {
+		t.Errorf("saved spike plot without group names (%v)", err)
+	}
+	spikes.Order = ByRate
+	err = MakeRateHeatmapPlot(spikes, RateHeatmap{BinWidth: 0.2}, "tests/groupRateHeatmapPlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+}
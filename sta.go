@@ -0,0 +1,153 @@
+package plots
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// STA is a spike-triggered average of a continuous signal, like a stimulus
+// or a membrane potential, over a window around the spikes of a spike line.
+// Only the spikes whose window is inside the signal time range contribute.
+type STA struct {
+	Title  string       // Title.
+	Line   SpikeLine    // Triggering spike line.
+	Signal plotter.XYer // Signal with X values sorted in increasing order.
+	Before float64      // Window duration before the spikes (default = 0.1).
+	After  float64      // Window duration after the spikes (default = 0).
+	Step   float64      // Sampling step of the average (default = window / 100).
+	YLabel string       // Y axis label (default = "Signal").
+	Color  color.Color  // Average line color (default = black).
+	BColor color.Color  // Standard error band color (default = Color).
+	XDim   vg.Length    // X dimension of saved plot, use default if 0.
+	YDim   vg.Length    // Y dimension of saved plot, use default if 0.
+}
+
+// Average returns the spike-triggered average and its standard error at the
+// lags relative to the spikes, and the number of contributing spikes.
+func (s STA) Average() (mean, se plotter.XYs, count int) {
+	before := s.Before
+	if before <= 0 && s.After <= 0 {
+		before = 0.1
+	}
+	before = max(before, 0)
+	after := max(s.After, 0)
+	step := s.Step
+	if step <= 0 {
+		step = (before + after) / 100
+	}
+	n := s.Signal.Len()
+	if n == 0 {
+		return nil, nil, 0
+	}
+	xMin, _ := s.Signal.XY(0)
+	xMax, _ := s.Signal.XY(n - 1)
+	nLags := int(math.Round((before+after)/step)) + 1
+	sum := make([]float64, nLags)
+	sum2 := make([]float64, nLags)
+	for _, v := range s.Line.Spikes {
+		if v-before < xMin || v+after > xMax {
+			continue
+		}
+		count++
+		for i := range sum {
+			y := traceValue(s.Signal, v-before+float64(i)*step)
+			sum[i] += y
+			sum2[i] += y * y
+		}
+	}
+	if count == 0 {
+		return nil, nil, 0
+	}
+	mean = make(plotter.XYs, nLags)
+	se = make(plotter.XYs, nLags)
+	for i := range mean {
+		m := sum[i] / float64(count)
+		mean[i] = plotter.XY{X: -before + float64(i)*step, Y: m}
+		se[i].X = mean[i].X
+		if count > 1 {
+			variance := max(sum2[i]-float64(count)*m*m, 0) / float64(count-1)
+			se[i].Y = math.Sqrt(variance / float64(count))
+		}
+	}
+	return mean, se, count
+}
+
+// newSTAPlot returns the spike-triggered average plot.
+func newSTAPlot(s STA) (*plot.Plot, error) {
+	mean, se, count := s.Average()
+	if count == 0 {
+		return nil, errors.New("no spike with a window in the signal range")
+	}
+	p := plot.New()
+	p.Title.Text = s.Title
+	p.X.Label.Text = "Time relative to spike (s)"
+	p.Y.Label.Text = s.YLabel
+	if p.Y.Label.Text == "" {
+		p.Y.Label.Text = "Signal"
+	}
+	lineColor := s.Color
+	if lineColor == nil {
+		lineColor = color.RGBA{0, 0, 0, 255}
+	}
+	bColor := s.BColor
+	if bColor == nil {
+		bColor = lineColor
+	}
+	band := make(plotter.XYs, 2*len(mean))
+	for i := range mean {
+		band[i] = plotter.XY{X: mean[i].X, Y: mean[i].Y + se[i].Y}
+		band[len(band)-1-i] = plotter.XY{X: mean[i].X, Y: mean[i].Y - se[i].Y}
+	}
+	poly, err := plotter.NewPolygon(band)
+	if err != nil {
+		return nil, err
+	}
+	poly.Color = withAlpha(bColor, 64)
+	poly.LineStyle.Width = 0
+	p.Add(poly)
+	line := &plotter.Line{
+		XYs: mean,
+		LineStyle: draw.LineStyle{
+			Color: lineColor,
+			Width: vg.Points(1.5),
+		},
+	}
+	p.Add(line)
+	p.Legend.Add(fmt.Sprintf("mean ± SE (n=%d)", count), line, poly)
+	p.Legend.Top = true
+
+	// dashed line at the spike time
+	p.Add(&plotter.Line{
+		XYs: plotter.XYs{{X: 0, Y: p.Y.Min}, {X: 0, Y: p.Y.Max}},
+		LineStyle: draw.LineStyle{
+			Color:  color.RGBA{160, 160, 160, 255},
+			Width:  vg.Points(1),
+			Dashes: Dashes.Id(1),
+		},
+	})
+	return p, nil
+}
+
+// MakeSTAPlot generates the spike-triggered average plot with its standard
+// error band. The legend shows the number of contributing spikes.
+func MakeSTAPlot(s STA, fileNames ...string) error {
+	p, err := newSTAPlot(s)
+	if err != nil {
+		return fmt.Errorf("sta plot: %w", err)
+	}
+	xDim, yDim := plotDims(s.XDim, s.YDim)
+	for _, fileName := range fileNames {
+		err := p.Save(xDim, yDim, fileName)
+		if err != nil {
+			return fmt.Errorf("sta plot: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package plots
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+)
+
+func TestSTAAverage(t *testing.T) {
+	s := STA{
+		Line:   SpikeLine{Spikes: []float64{0.2, 1, 2, 3.9}},
+		Signal: plotter.XYs{{X: 0, Y: 0}, {X: 4, Y: 4}},
+		Before: 0.5,
+		After:  0.25,
+		Step:   0.25,
+	}
+	mean, se, count := s.Average()
+	if count != 2 || len(mean) != 4 || len(se) != 4 {
+		t.Fatalf("got %d spikes and %d lags, expected 2 and 4", count, len(mean))
+	}
+	for i := range mean {
+		if math.Abs(mean[i].Y-(1.5+mean[i].X)) > 1e-12 || math.Abs(se[i].Y-0.5) > 1e-12 {
+			t.Errorf("got mean %v and SE %v at lag %v", mean[i].Y, se[i].Y, mean[i].X)
+		}
+	}
+	s.Line.Spikes = []float64{0.1}
+	if _, _, count := s.Average(); count != 0 {
+		t.Errorf("got %d spikes, expected 0", count)
+	}
+}
+
+func TestSTAPlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	// low pass filtered noise stimulus
+	const dt = 0.001
+	stimulus := make(plotter.XYs, 20000)
+	var v float64
+	for i := range stimulus {
+		v += dt/0.02*(-v) + math.Sqrt(2*dt/0.02)*rng.NormFloat64()
+		stimulus[i] = plotter.XY{X: float64(i) * dt, Y: v}
+	}
+	// the firing rate is proportional to the positive stimulus with a latency
+	g := Generator{Refractory: 0.002}
+	line := g.InhomogeneousPoisson(func(t float64) float64 {
+		return 50 * max(traceValue(stimulus, t-0.015), 0)
+	}, 200, 20)
+	err := MakeSTAPlot(STA{
+		Title:  "Spike-triggered average",
+		Line:   line,
+		Signal: stimulus,
+		Before: 0.1,
+		After:  0.05,
+		YLabel: "Stimulus",
+		Color:  DarkColors.Id(2),
+	}, "tests/staPlot.png", "tests/staPlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	err = MakeSTAPlot(STA{Signal: stimulus}, "tests/staEmptyPlot.png")
+	if err == nil {
+		t.Fatalf("expected error without spikes")
+	}
+}
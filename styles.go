@@ -45,6 +45,13 @@ func rgb(r, g, b uint8) color.RGBA {
 	return color.RGBA{r, g, b, 255}
 }
 
+// withAlpha returns the color c with its alpha channel set to alpha.
+func withAlpha(c color.Color, alpha uint8) color.NRGBA {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nc.A = alpha
+	return nc
+}
+
 // GlyphTable is a table of glyphs one can pick from.
 type GlyphTable []draw.GlyphDrawer
 
@@ -0,0 +1,136 @@
+package plots
+
+import (
+	"image/color"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Synchrony is the detection and drawing property of synchronous events
+// of spike lines.
+type Synchrony struct {
+	Window     float64     // Coincidence tolerance window.
+	MinLines   int         // Minimum number of participating lines (default = 2).
+	Color      color.Color // Event bar color (default = grey).
+	Width      vg.Length   // Event bar width (default = vg.Points(1)).
+	SpikeColor color.Color // Coincident spike color, unchanged if nil.
+}
+
+// SyncEvent is a synchronous event of spike lines.
+type SyncEvent struct {
+	Time   float64 // Mean time of the participating spikes.
+	Lines  []int   // Index of the participating lines in increasing order.
+	Spikes []int   // Index of the participating spike in each line.
+}
+
+// DetectSync returns the synchronous events in increasing time order. An
+// event is a set of spikes of at least minLines distinct lines in a time
+// window starting at its first spike. Each spike belongs to at most one
+// event, and events are searched greedily from the earliest spike.
+func DetectSync(lines []SpikeLine, window float64, minLines int) []SyncEvent {
+	if minLines < 2 {
+		minLines = 2
+	}
+	type spikeRef struct {
+		time        float64
+		line, spike int
+	}
+	var spikes []spikeRef
+	for i := range lines {
+		for j, v := range lines[i].Spikes {
+			spikes = append(spikes, spikeRef{time: v, line: i, spike: j})
+		}
+	}
+	sort.Slice(spikes, func(i, j int) bool {
+		return spikes[i].time < spikes[j].time
+	})
+
+	var events []SyncEvent
+	used := make([]bool, len(spikes))
+	inEvent := make([]bool, len(lines))
+	var members []int
+	for i := range spikes {
+		if used[i] {
+			continue
+		}
+		members = members[:0]
+		for j := i; j < len(spikes) && spikes[j].time <= spikes[i].time+window; j++ {
+			if used[j] || inEvent[spikes[j].line] {
+				continue
+			}
+			inEvent[spikes[j].line] = true
+			members = append(members, j)
+		}
+		for _, j := range members {
+			inEvent[spikes[j].line] = false
+		}
+		if len(members) < minLines {
+			continue
+		}
+		sort.Slice(members, func(a, b int) bool {
+			return spikes[members[a]].line < spikes[members[b]].line
+		})
+		event := SyncEvent{
+			Lines:  make([]int, len(members)),
+			Spikes: make([]int, len(members)),
+		}
+		for k, j := range members {
+			used[j] = true
+			event.Time += spikes[j].time
+			event.Lines[k] = spikes[j].line
+			event.Spikes[k] = spikes[j].spike
+		}
+		event.Time /= float64(len(members))
+		events = append(events, event)
+	}
+	return events
+}
+
+// coincidentSpikes returns for each line a flag per spike set to true when
+// the spike belongs to one of the events.
+func coincidentSpikes(lines []SpikeLine, events []SyncEvent) [][]bool {
+	flags := make([][]bool, len(lines))
+	for i := range lines {
+		flags[i] = make([]bool, len(lines[i].Spikes))
+	}
+	for _, e := range events {
+		for k, i := range e.Lines {
+			flags[i][e.Spikes[k]] = true
+		}
+	}
+	return flags
+}
+
+// draw draws a bar for each event spanning from the lowest to the highest
+// participating line among nLines lines drawn from top to bottom.
+func (y *Synchrony) draw(canvas draw.Canvas, plt *plot.Plot, events []SyncEvent, nLines int) {
+	trX, trY := plt.Transforms(&canvas)
+	dy := (plt.Y.Max - plt.Y.Min) / float64(nLines)
+	lineStyle := draw.LineStyle{
+		Color: color.RGBA{160, 160, 160, 255},
+		Width: vg.Points(1),
+	}
+	if y.Color != nil {
+		lineStyle.Color = y.Color
+	}
+	if y.Width != 0 {
+		lineStyle.Width = y.Width
+	}
+	canvas.SetLineStyle(lineStyle)
+	for _, e := range events {
+		if e.Time < plt.X.Min || e.Time > plt.X.Max {
+			continue
+		}
+		top, bottom := e.Lines[0], e.Lines[len(e.Lines)-1]
+		yMin := plt.Y.Min + dy*float64(nLines-bottom-1)
+		yMax := plt.Y.Min + dy*float64(nLines-top-1) + 3*dy/4
+		var path vg.Path
+		xPixel := trX(e.Time)
+		path.Move(vg.Point{X: xPixel, Y: trY(yMin)})
+		path.Line(vg.Point{X: xPixel, Y: trY(yMax)})
+		canvas.Stroke(path)
+	}
+}
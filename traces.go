@@ -0,0 +1,208 @@
+package plots
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Traces are the membrane potential traces of selected spike lines drawn in
+// panels stacked above the spike plot, with the same time range.
+type Traces struct {
+	Traces       map[string]plotter.XYer // Membrane potential trace by spike line label, in spike time unit.
+	Thresholds   map[string]float64      // Firing threshold by spike line label, none if absent.
+	Unit         string                  // Trace unit appended to the panel labels (default = "mV").
+	Color        color.Color             // Trace color (default = line spike color).
+	Width        vg.Length               // Trace width (default = vg.Points(1)).
+	TColor       color.Color             // Threshold line color (default = grey).
+	MColor       color.Color             // Spike mark color (default = red).
+	MRadius      vg.Length               // Spike mark radius (default = vg.Points(2)).
+	RasterHeight float64                 // Spike plot fraction of plot height (default = 0.5).
+}
+
+// unlabeledTicks are the ticks of a ticker without labels. The ticker is
+// plot.DefaultTicks if nil.
+type unlabeledTicks struct {
+	plot.Ticker
+}
+
+// Ticks returns the ticks without labels.
+func (t unlabeledTicks) Ticks(min, max float64) []plot.Tick {
+	ticker := t.Ticker
+	if ticker == nil {
+		ticker = plot.DefaultTicks{}
+	}
+	ticks := ticker.Ticks(min, max)
+	for i := range ticks {
+		ticks[i].Label = ""
+	}
+	return ticks
+}
+
+// traceValue returns the value of the trace at x by linear interpolation.
+// Requires that the trace X values are sorted in increasing order.
+func traceValue(xy plotter.XYer, x float64) float64 {
+	n := xy.Len()
+	k := sort.Search(n, func(i int) bool {
+		xi, _ := xy.XY(i)
+		return xi >= x
+	})
+	switch {
+	case n == 0:
+		return 0
+	case k == 0:
+		_, y := xy.XY(0)
+		return y
+	case k == n:
+		_, y := xy.XY(n - 1)
+		return y
+	}
+	x0, y0 := xy.XY(k - 1)
+	x1, y1 := xy.XY(k)
+	if x1 == x0 {
+		return y1
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// convertedXYer is a trace with its X values converted to the display unit
+// of the spike plot.
+type convertedXYer struct {
+	plotter.XYer
+	origin, scale float64
+}
+
+// XY returns the converted point i.
+func (c convertedXYer) XY(i int) (x, y float64) {
+	x, y = c.XYer.XY(i)
+	return (x - c.origin) * c.scale, y
+}
+
+// newTracePlot returns the plot of the trace of the spike line in the time
+// range [xMin,xMax], with its threshold and spike marks.
+func newTracePlot(t Traces, xy plotter.XYer, l *SpikeLine, spikeColor color.Color, xMin, xMax float64) (*plot.Plot, error) {
+	p := plot.New()
+	unit := t.Unit
+	if unit == "" {
+		unit = "mV"
+	}
+	p.Y.Label.Text = fmt.Sprintf("%s (%s)", l.Label, unit)
+	p.X.Tick.Marker = unlabeledTicks{}
+	if threshold, ok := t.Thresholds[l.Label]; ok {
+		tColor := t.TColor
+		if tColor == nil {
+			tColor = color.RGBA{160, 160, 160, 255}
+		}
+		p.Add(&plotter.Line{
+			XYs: plotter.XYs{{X: xMin, Y: threshold}, {X: xMax, Y: threshold}},
+			LineStyle: draw.LineStyle{
+				Color:  tColor,
+				Width:  vg.Points(1),
+				Dashes: Dashes.Id(1),
+			},
+		})
+	}
+	line, err := plotter.NewLine(xy)
+	if err != nil {
+		return nil, err
+	}
+	line.Color = spikeColor
+	if t.Color != nil {
+		line.Color = t.Color
+	}
+	if t.Width != 0 {
+		line.Width = t.Width
+	}
+	p.Add(line)
+
+	// mark the spike instants on the trace
+	beg := sort.SearchFloat64s(l.Spikes, xMin)
+	end := sort.SearchFloat64s(l.Spikes, xMax)
+	if end > beg {
+		marks := make(plotter.XYs, 0, end-beg)
+		for _, v := range l.Spikes[beg:end] {
+			marks = append(marks, plotter.XY{X: v, Y: traceValue(xy, v)})
+		}
+		scatter, err := plotter.NewScatter(marks)
+		if err != nil {
+			return nil, err
+		}
+		scatter.Color = color.RGBA{238, 46, 47, 255}
+		if t.MColor != nil {
+			scatter.Color = t.MColor
+		}
+		scatter.Radius = vg.Points(2)
+		if t.MRadius != 0 {
+			scatter.Radius = t.MRadius
+		}
+		p.Add(scatter)
+	}
+	p.X.Min, p.X.Max = xMin, xMax
+	return p, nil
+}
+
+// MakeTracePlot generates the spike plot with the membrane potential traces
+// of the selected spike lines stacked above it. The trace panels are in the
+// order of the spike lines and share the time range of the spike plot.
+func MakeTracePlot(spikeLines SpikeLines, t Traces, fileNames ...string) error {
+	if len(fileNames) == 0 {
+		return nil
+	}
+	if err := spikeLines.checkUnits(); err != nil {
+		return fmt.Errorf("trace plot: %w", err)
+	}
+	bottom := NewSpikePlot(spikeLines)
+	xMin, xMax := bottom.X.Min, bottom.X.Max
+	_, origin, scale := spikeLines.conversion()
+	var panels []panel
+	for i := range bottom.lines.Lines {
+		l := &bottom.lines.Lines[i]
+		xy := t.Traces[l.Label]
+		if xy == nil {
+			continue
+		}
+		if origin != 0 || scale != 1 {
+			xy = convertedXYer{XYer: xy, origin: origin, scale: scale}
+		}
+		spikeColor := l.Property.Color
+		if spikeColor == nil {
+			spikeColor = bottom.lines.groupColor(l.Group)
+		}
+		if spikeColor == nil {
+			spikeColor = color.RGBA{0, 0, 0, 255}
+		}
+		p, err := newTracePlot(t, xy, l, spikeColor, xMin, xMax)
+		if err != nil {
+			return fmt.Errorf("trace plot: %s: %w", l.Label, err)
+		}
+		panels = append(panels, p)
+	}
+	if len(panels) == 0 {
+		return fmt.Errorf("trace plot: no trace for the spike lines")
+	}
+
+	// the title is drawn at the top of the first trace panel
+	panels[0].(*plot.Plot).Title.Text = bottom.Title.Text
+	bottom.Title.Text = ""
+	rasterHeight := t.RasterHeight
+	if rasterHeight <= 0 || rasterHeight >= 1 {
+		rasterHeight = 0.5
+	}
+	weights := make([]float64, len(panels), len(panels)+1)
+	for i := range weights {
+		weights[i] = (1 - rasterHeight) / float64(len(panels))
+	}
+	panels = append(panels, bottom)
+	weights = append(weights, rasterHeight)
+	xDim, yDim := plotDims(spikeLines.XDim, spikeLines.YDim)
+	err := saveStacked(panels, weights, xDim, yDim, fileNames...)
+	if err != nil {
+		return fmt.Errorf("trace plot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func TestTraceValue(t *testing.T) {
+	xy := plotter.XYs{{X: 0, Y: 0}, {X: 1, Y: 10}, {X: 2, Y: 0}}
+	for _, test := range []struct{ x, y float64 }{
+		{-1, 0}, {0.5, 5}, {1, 10}, {1.25, 7.5}, {3, 0},
+	} {
+		if y := traceValue(xy, test.x); y != test.y {
+			t.Errorf("got value %v at %v, expected %v", y, test.x, test.y)
+		}
+	}
+}
+
+func TestTracePlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	// leaky integrate and fire neurons with a noisy input current
+	const (
+		dt        = 0.0005
+		duration  = 1.
+		tau       = 0.02
+		vRest     = -70.
+		vReset    = -75.
+		threshold = -50.
+	)
+	var spikes SpikeLines
+	traces := Traces{
+		Traces:     make(map[string]plotter.XYer),
+		Thresholds: make(map[string]float64),
+	}
+	for i := 0; i < 10; i++ {
+		label := fmt.Sprintf("%d", i+1)
+		input := 18 + 2*float64(i)
+		v := vRest
+		var trace plotter.XYs
+		var line []float64
+		for k := 0; k < int(duration/dt); k++ {
+			x := float64(k) * dt
+			v += dt/tau*(vRest-v+input) + 2*math.Sqrt(dt/tau)*rng.NormFloat64()
+			if v >= threshold {
+				line = append(line, x)
+				trace = append(trace, plotter.XY{X: x, Y: 0})
+				v = vReset
+			}
+			trace = append(trace, plotter.XY{X: x, Y: v})
+		}
+		spikes.Lines = append(spikes.Lines, SpikeLine{Label: label, Spikes: line})
+		if i%4 == 1 {
+			traces.Traces[label] = trace
+			traces.Thresholds[label] = threshold
+		}
+	}
+	spikes.Title = "Membrane potentials"
+	spikes.YDim = 20 * vg.Centimeter
+	err := MakeTracePlot(spikes, traces, "tests/tracePlot.png", "tests/tracePlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	err = MakeTracePlot(spikes, Traces{})
+	if err != nil {
+		t.Fatalf("unexpected error without file names: %s", err)
+	}
+}
@@ -0,0 +1,124 @@
+package plots
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// Trials is a raster of repeated trials of a neuron with one spike line per
+// trial. The spikes of each trial are aligned to the trial event time, like
+// a stimulus onset or a response, and the trials may be sorted by a
+// behavioural variable.
+type Trials struct {
+	Title  string      // Title.
+	Lines  []SpikeLine // Spike line of each trial, labeled by trial number if empty.
+	Events []float64   // Alignment event time of each trial, none if nil.
+	SortBy []float64   // Behavioural variable of each trial to sort by, trial order if nil, last if missing.
+	XLimit *Limit      // Aligned spike time range limit.
+	Epochs Epochs      // Aligned time intervals drawn as bands behind the spikes.
+	EColor color.Color // Alignment event line color (default = red).
+	XDim   vg.Length   // X dimension of saved plot, use default if 0.
+	YDim   vg.Length   // Y dimension of saved plot, use default if 0.
+}
+
+// Aligned returns the spike lines of the trials with the spike times
+// relative to the trial event time. The lines are in trial order and their
+// Order sorts them by increasing SortBy value, with the trials without value
+// last.
+func (t Trials) Aligned() SpikeLines {
+	s := SpikeLines{
+		Title:  t.Title,
+		Lines:  make([]SpikeLine, len(t.Lines)),
+		XLimit: t.XLimit,
+		Epochs: t.Epochs,
+		XDim:   t.XDim,
+		YDim:   t.YDim,
+	}
+	for i, l := range t.Lines {
+		if l.Label == "" {
+			l.Label = strconv.Itoa(i + 1)
+		}
+		var event float64
+		if i < len(t.Events) {
+			event = t.Events[i]
+		}
+		spikes := make([]float64, len(l.Spikes))
+		for k, v := range l.Spikes {
+			spikes[k] = v - event
+		}
+		l.Spikes = spikes
+		s.Lines[i] = l
+	}
+	if t.SortBy != nil {
+		key := func(i int) float64 {
+			if i < len(t.SortBy) {
+				return t.SortBy[i]
+			}
+			return math.Inf(1)
+		}
+		perm := identity(len(t.Lines))
+		sort.SliceStable(perm, func(i, j int) bool {
+			return key(perm[i]) < key(perm[j])
+		})
+		s.Order = ByPermutation(perm)
+	}
+	return s
+}
+
+// eventLine is a vertical line at an X value over the whole plot height.
+type eventLine struct {
+	X float64
+	draw.LineStyle
+}
+
+// Plot draws the vertical line.
+func (e eventLine) Plot(canvas draw.Canvas, plt *plot.Plot) {
+	if e.X < plt.X.Min || e.X > plt.X.Max {
+		return
+	}
+	trX, _ := plt.Transforms(&canvas)
+	x := trX(e.X)
+	canvas.StrokeLine2(e.LineStyle, x, canvas.Min.Y, x, canvas.Max.Y)
+}
+
+// MakeTrialPlot generates the raster of the aligned trials with the
+// peri-stimulus time histogram of the aligned trials stacked below it, as
+// MakeSpikePSTHPlot does. A vertical line marks the alignment event time.
+// The PSTH Lines, XLimit, Title, XDim and YDim are ignored.
+func MakeTrialPlot(t Trials, h PSTH, fileNames ...string) error {
+	if t.Events != nil && len(t.Events) != len(t.Lines) {
+		return fmt.Errorf("trial plot: %d events for %d trials", len(t.Events), len(t.Lines))
+	}
+	if t.SortBy != nil && len(t.SortBy) != len(t.Lines) {
+		return fmt.Errorf("trial plot: %d sort values for %d trials", len(t.SortBy), len(t.Lines))
+	}
+	spikeLines := t.Aligned()
+	h.Lines = nil
+	top, bottom := newSpikePSTHPlots(spikeLines, h)
+	top.Y.Label.Text = "Trial"
+	event := eventLine{
+		LineStyle: draw.LineStyle{
+			Color:  color.RGBA{238, 46, 47, 255},
+			Width:  vg.Points(1),
+			Dashes: Dashes.Id(1),
+		},
+	}
+	if t.EColor != nil {
+		event.Color = t.EColor
+	}
+	top.Add(event)
+	bottom.Add(event)
+	xDim, yDim := plotDims(t.XDim, t.YDim)
+	err := saveStacked([]panel{top, bottom}, []float64{3, 1}, xDim, yDim, fileNames...)
+	if err != nil {
+		return fmt.Errorf("trial plot: %w", err)
+	}
+	return nil
+}
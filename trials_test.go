@@ -0,0 +1,76 @@
+package plots
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestTrialsAligned(t *testing.T) {
+	trials := Trials{
+		Lines:  []SpikeLine{{Spikes: []float64{1, 2}}, {Label: "b", Spikes: []float64{10.5}}},
+		Events: []float64{1, 10},
+		SortBy: []float64{2, 1},
+	}
+	s := trials.Aligned()
+	if fmt.Sprint(s.Lines[0].Spikes, s.Lines[1].Spikes) != "[0 1] [0.5]" {
+		t.Errorf("got aligned spikes %v %v", s.Lines[0].Spikes, s.Lines[1].Spikes)
+	}
+	if trials.Lines[0].Spikes[0] != 1 {
+		t.Errorf("trial spikes were modified")
+	}
+	var labels []string
+	for _, l := range s.Sorted().Lines {
+		labels = append(labels, l.Label)
+	}
+	if fmt.Sprint(labels) != "[b 1]" {
+		t.Errorf("got sorted labels %v, expected [b 1]", labels)
+	}
+
+	// trials without SortBy value are last
+	trials.Lines = append(trials.Lines, SpikeLine{Label: "c"})
+	trials.SortBy = []float64{2}
+	labels = labels[:0]
+	for _, l := range trials.Aligned().Sorted().Lines {
+		labels = append(labels, l.Label)
+	}
+	if fmt.Sprint(labels) != "[1 b c]" {
+		t.Errorf("got sorted labels %v, expected [1 b c]", labels)
+	}
+}
+
+func TestTrialPlot(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	g := Generator{Refractory: 0.002}
+	var trials Trials
+	var t0 float64
+	for i := 0; i < 40; i++ {
+		// background activity with a response burst after a variable latency
+		latency := 0.05 + 0.2*rng.Float64()
+		l := g.Poisson(5, 1.5)
+		burst := g.Poisson(80, 0.1)
+		for k := range burst.Spikes {
+			burst.Spikes[k] += 0.5 + latency
+		}
+		l.Spikes = g.Embed([]SpikeLine{l}, []int{0}, burst.Spikes, 0, -1)[0].Spikes
+		for k := range l.Spikes {
+			l.Spikes[k] += t0
+		}
+		trials.Lines = append(trials.Lines, l)
+		trials.Events = append(trials.Events, t0+0.5)
+		trials.SortBy = append(trials.SortBy, latency)
+		t0 += 2
+	}
+	trials.Title = "Trials sorted by latency"
+	trials.XLimit = &Limit{Min: -0.5, Max: 1}
+	trials.Epochs = Epochs{{Start: 0, End: 0.3, Label: "stimulus"}}
+	err := MakeTrialPlot(trials, PSTH{BinWidth: 0.025, Rate: true, Smoothing: GaussianSmoothing}, "tests/trialPlot.png", "tests/trialPlot.svg")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	trials.Events = trials.Events[1:]
+	err = MakeTrialPlot(trials, PSTH{}, "tests/trialPlot.png")
+	if err == nil {
+		t.Fatalf("expected error with missing events")
+	}
+}
@@ -0,0 +1,146 @@
+package plots
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/plot"
+)
+
+// TimeUnit is a spike time unit.
+type TimeUnit struct {
+	Name  string  // Unit name shown in the axis labels.
+	Scale float64 // Unit duration in seconds, or 0 if unknown like for steps.
+}
+
+var (
+	Seconds      = TimeUnit{Name: "s", Scale: 1}
+	Milliseconds = TimeUnit{Name: "ms", Scale: 1e-3}
+	Microseconds = TimeUnit{Name: "µs", Scale: 1e-6}
+	Steps        = TimeUnit{Name: "steps"}
+)
+
+// orSeconds returns the unit, or Seconds if the unit is the zero value.
+func (u TimeUnit) orSeconds() TimeUnit {
+	if u == (TimeUnit{}) {
+		return Seconds
+	}
+	return u
+}
+
+// timeLabel returns the time axis label of the unit.
+func (u TimeUnit) timeLabel() string {
+	return fmt.Sprintf("Time (%s)", u.orSeconds().Name)
+}
+
+// rateLabel returns the rate axis label of the unit.
+func (u TimeUnit) rateLabel() string {
+	if u.orSeconds().Scale > 0 {
+		return "Rate (Hz)"
+	}
+	return fmt.Sprintf("Rate (1/%s)", u.Name)
+}
+
+// perSecond returns the factor converting rates per unit to rates in Hz,
+// or 1 if the unit duration is unknown.
+func (u TimeUnit) perSecond() float64 {
+	if u = u.orSeconds(); u.Scale > 0 {
+		return 1 / u.Scale
+	}
+	return 1
+}
+
+// timeTicks are the default ticks, with only integer major ticks for the
+// units of unknown duration like steps.
+type timeTicks struct {
+	unit TimeUnit
+}
+
+// Ticks returns the ticks in [min,max].
+func (t timeTicks) Ticks(min, max float64) []plot.Tick {
+	ticks := plot.DefaultTicks{}.Ticks(min, max)
+	if t.unit.orSeconds().Scale > 0 {
+		return ticks
+	}
+	for i := range ticks {
+		if ticks[i].Label != "" && ticks[i].Value != math.Trunc(ticks[i].Value) {
+			ticks[i].Label = ""
+		}
+	}
+	return ticks
+}
+
+// checkUnits returns an error if the spike times can't be converted to
+// DisplayUnit because the duration of one of the units is unknown.
+func (s SpikeLines) checkUnits() error {
+	unit := s.Unit.orSeconds()
+	if s.DisplayUnit != (TimeUnit{}) && s.DisplayUnit != unit && !(unit.Scale > 0 && s.DisplayUnit.Scale > 0) {
+		return fmt.Errorf("can't convert %s to %s with an unknown unit duration", unit.Name, s.DisplayUnit.Name)
+	}
+	return nil
+}
+
+// conversion returns the display unit of the spike lines, and the origin
+// and scale of the conversion of the spike times to this unit. DisplayUnit
+// is ignored when the conversion is not possible (see checkUnits).
+func (s SpikeLines) conversion() (unit TimeUnit, origin, scale float64) {
+	unit, scale = s.Unit.orSeconds(), 1
+	if s.checkUnits() == nil && s.DisplayUnit != (TimeUnit{}) && s.DisplayUnit != unit {
+		scale = unit.Scale / s.DisplayUnit.Scale
+		unit = s.DisplayUnit
+	}
+	if s.Relative && s.XLimit != nil {
+		origin = s.XLimit.Min
+	}
+	return unit, origin, scale
+}
+
+// converted returns the spike lines with the spike times, XLimit, epochs and
+// synchrony window converted to DisplayUnit, and with times relative to
+// XLimit.Min when Relative is true. The spike slices are copied when
+// modified.
+func (s SpikeLines) converted() SpikeLines {
+	unit, origin, scale := s.conversion()
+	s.Unit, s.DisplayUnit, s.Relative = unit, TimeUnit{}, false
+	if scale == 1 && origin == 0 {
+		return s
+	}
+	convert := func(v float64) float64 {
+		return (v - origin) * scale
+	}
+	lines := make([]SpikeLine, len(s.Lines))
+	for i, l := range s.Lines {
+		spikes := make([]float64, len(l.Spikes))
+		for k, v := range l.Spikes {
+			spikes[k] = convert(v)
+		}
+		l.Spikes = spikes
+		lines[i] = l
+	}
+	s.Lines = lines
+	if s.XLimit != nil {
+		s.XLimit = &Limit{Min: convert(s.XLimit.Min), Max: convert(s.XLimit.Max)}
+	}
+	if s.Epochs != nil {
+		epochs := make(Epochs, len(s.Epochs))
+		for i, e := range s.Epochs {
+			e.Start, e.End = convert(e.Start), convert(e.End)
+			epochs[i] = e
+		}
+		s.Epochs = epochs
+	}
+	if s.Sync != nil {
+		sync := *s.Sync
+		sync.Window *= scale
+		s.Sync = &sync
+	}
+	return s
+}
+
+// xLabel returns the X axis label of the spike plot.
+func (s SpikeLines) xLabel() string {
+	if s.XLabel != "" {
+		return s.XLabel
+	}
+	return s.Unit.timeLabel()
+}
@@ -0,0 +1,78 @@
+package plots
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestConvertedSpikeLines(t *testing.T) {
+	s := SpikeLines{
+		Lines:       []SpikeLine{{Spikes: []float64{1500, 2500}}},
+		Unit:        Milliseconds,
+		DisplayUnit: Seconds,
+		Relative:    true,
+		XLimit:      &Limit{Min: 1000, Max: 3000},
+		Epochs:      Epochs{{Start: 2000, End: 2200}},
+	}
+	c := s.converted()
+	if fmt.Sprint(c.Lines[0].Spikes, *c.XLimit, c.Epochs[0].Start, c.Epochs[0].End) != "[0.5 1.5] {0 2} 1 1.2" {
+		t.Errorf("got converted %v %v %v %v", c.Lines[0].Spikes, *c.XLimit, c.Epochs[0].Start, c.Epochs[0].End)
+	}
+	if c.Unit != Seconds || c.xLabel() != "Time (s)" {
+		t.Errorf("got unit %v and label %q", c.Unit, c.xLabel())
+	}
+	if s.Lines[0].Spikes[0] != 1500 {
+		t.Errorf("spike lines were modified")
+	}
+	if c := c.converted(); c.Lines[0].Spikes[0] != 0.5 {
+		t.Errorf("converted spike lines were converted again")
+	}
+	h := PSTH{Lines: s.Lines, BinWidth: 500, Rate: true, Unit: Milliseconds}
+	if bins := h.Bins(1000, 3000); bins[1].Weight != 2 {
+		t.Errorf("got rate %v, expected 2 Hz", bins[1].Weight)
+	}
+	ticks := timeTicks{unit: Steps}.Ticks(0, 2)
+	for _, tick := range ticks {
+		if tick.Label != "" && tick.Value != float64(int(tick.Value)) {
+			t.Errorf("got step tick label %q at %v", tick.Label, tick.Value)
+		}
+	}
+}
+
+func TestSpikePlotUnits(t *testing.T) {
+	os.MkdirAll("tests", 0766)
+	var spikes SpikeLines
+	for i := 0; i < 10; i++ {
+		// spike times in 0.1 ms simulation steps
+		l := SpikeLine{Label: fmt.Sprintf("%d", i+1)}
+		for _, v := range GeneratePoissonDistributedSpikes(4, 8, 0.02) {
+			l.Spikes = append(l.Spikes, float64(int(v*10000)))
+		}
+		spikes.Lines = append(spikes.Lines, l)
+	}
+	spikes.Title = "Simulation steps"
+	spikes.Unit = Steps
+	spikes.XLimit = &Limit{Min: 10000, Max: 12000}
+	err := MakeSpikePlot(spikes, "tests/stepSpikePlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	spikes.Title = "Relative time in ms"
+	spikes.Unit = TimeUnit{Name: "0.1 ms", Scale: 1e-4}
+	spikes.DisplayUnit = Milliseconds
+	spikes.XLimit = &Limit{Min: 10000, Max: 20000}
+	spikes.Relative = true
+	spikes.Epochs = Epochs{{Start: 12000, End: 15000, Label: "stimulus"}}
+	err = MakeSpikePSTHPlot(spikes, PSTH{Rate: true}, "tests/unitSpikePSTHPlot.png")
+	if err != nil {
+		t.Fatalf("failed saving image: %s", err)
+	}
+	spikes.Unit, spikes.DisplayUnit = Steps, Seconds
+	if err = MakeSpikePlot(spikes, "tests/stepSecondsSpikePlot.png"); err == nil {
+		t.Fatal("expected an error converting steps to seconds")
+	}
+	if unit, _, scale := spikes.conversion(); unit != Steps || scale != 1 {
+		t.Fatalf("got conversion to %v with scale %g, expected steps with scale 1", unit, scale)
+	}
+}